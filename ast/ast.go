@@ -2,6 +2,8 @@ package ast
 
 import (
 	"bytes"
+	"math/big"
+	"strconv"
 	"strings"
 
 	"github.com/anilang-official/AniLang/token"
@@ -10,8 +12,21 @@ import (
 type Node interface {
 	TokenLiteral() string
 	String() string
+	Pos() token.Position     // Position of the first character of the node
+	End() token.Position     // Position immediately after the node
+	SetSpan(span token.Span) // Records the source span the node was parsed from
 }
 
+// Location is embedded in every node and records the source span the node
+// was parsed from
+type Location struct {
+	Span token.Span
+}
+
+func (l *Location) Pos() token.Position     { return l.Span.Start }
+func (l *Location) End() token.Position     { return l.Span.End }
+func (l *Location) SetSpan(span token.Span) { l.Span = span }
+
 type Statement interface {
 	Node
 	statementNode()
@@ -22,7 +37,15 @@ type Expression interface {
 	expressionNode()
 }
 
+// Pattern is matched against a value, binding the names it contains when it
+// matches
+type Pattern interface {
+	Node
+	patternNode()
+}
+
 type Program struct {
+	Location
 	Statements []Statement
 }
 
@@ -45,6 +68,7 @@ func (p *Program) String() string {
 }
 
 type BlockStatement struct {
+	Location
 	Token      token.Token // the { token
 	Statements []Statement
 }
@@ -62,8 +86,10 @@ func (bs *BlockStatement) String() string {
 }
 
 type LetStatement struct {
+	Location
 	Token      token.Token // the token.Let token
 	Name       *Identifier
+	Pattern    Pattern // Set instead of Name when destructuring, as in let [a, b] = pair;
 	Assignment token.Token
 	Value      Expression
 }
@@ -74,7 +100,11 @@ func (ls *LetStatement) String() string {
 	var out bytes.Buffer
 
 	out.WriteString(ls.TokenLiteral() + " ")
-	out.WriteString(ls.Name.String())
+	if ls.Pattern != nil {
+		out.WriteString(ls.Pattern.String())
+	} else {
+		out.WriteString(ls.Name.String())
+	}
 	out.WriteString(" " + ls.Assignment.Literal + " ")
 
 	if ls.Value != nil {
@@ -86,6 +116,7 @@ func (ls *LetStatement) String() string {
 }
 
 type ReturnStatement struct {
+	Location
 	Token       token.Token // the 'return' token
 	ReturnValue Expression
 }
@@ -106,6 +137,7 @@ func (rs *ReturnStatement) String() string {
 }
 
 type BreakStatement struct {
+	Location
 	Token token.Token // The 'break' token
 }
 
@@ -121,6 +153,7 @@ func (be *BreakStatement) String() string {
 }
 
 type ContinueStatement struct {
+	Location
 	Token token.Token // The 'continue' token
 }
 
@@ -136,6 +169,7 @@ func (ce *ContinueStatement) String() string {
 }
 
 type ExpressionStatement struct {
+	Location
 	Token      token.Token // the first token of the expression
 	Expression Expression
 }
@@ -150,6 +184,7 @@ func (es *ExpressionStatement) String() string {
 }
 
 type PrefixExpression struct {
+	Location
 	Token    token.Token // The prefix token, e.g. !
 	Operator string
 	Right    Expression
@@ -169,6 +204,7 @@ func (pe *PrefixExpression) String() string {
 }
 
 type InfixExpression struct {
+	Location
 	Token    token.Token // The operator token, e.g. +
 	Left     Expression
 	Operator string
@@ -189,7 +225,83 @@ func (oe *InfixExpression) String() string {
 	return out.String()
 }
 
+// RangeExpression builds a range such as 1..10, 0..<n or 10..1 step -1
+type RangeExpression struct {
+	Location
+	Token     token.Token // The .. or ..< token
+	From      Expression
+	To        Expression
+	Step      Expression // nil when no step is given
+	Inclusive bool
+}
+
+func (re *RangeExpression) expressionNode()      {}
+func (re *RangeExpression) TokenLiteral() string { return re.Token.Literal }
+func (re *RangeExpression) String() string {
+	var out bytes.Buffer
+
+	out.WriteString(token.LPAREN)
+	out.WriteString(re.From.String())
+	out.WriteString(re.Token.Literal)
+	out.WriteString(re.To.String())
+	if re.Step != nil {
+		out.WriteString(" step " + re.Step.String())
+	}
+	out.WriteString(token.RPAREN)
+
+	return out.String()
+}
+
+// LogicalExpression is a && or || expression. It is kept apart from
+// InfixExpression because its right side is only evaluated when needed.
+type LogicalExpression struct {
+	Location
+	Token    token.Token // The && or || token
+	Left     Expression
+	Operator string
+	Right    Expression
+}
+
+func (le *LogicalExpression) expressionNode()      {}
+func (le *LogicalExpression) TokenLiteral() string { return le.Token.Literal }
+func (le *LogicalExpression) String() string {
+	var out bytes.Buffer
+
+	out.WriteString(token.LPAREN)
+	out.WriteString(le.Left.String())
+	out.WriteString(" " + le.Operator + " ")
+	out.WriteString(le.Right.String())
+	out.WriteString(token.RPAREN)
+
+	return out.String()
+}
+
+// AssignExpression assigns to an existing variable or to an element of an
+// array or hash, e.g. x = 5, x += 1 or scores["ani"] = 10
+type AssignExpression struct {
+	Location
+	Token    token.Token // The assignment operator token, e.g. +=
+	Target   Expression
+	Operator string
+	Value    Expression
+}
+
+func (ae *AssignExpression) expressionNode()      {}
+func (ae *AssignExpression) TokenLiteral() string { return ae.Token.Literal }
+func (ae *AssignExpression) String() string {
+	var out bytes.Buffer
+
+	out.WriteString(token.LPAREN)
+	out.WriteString(ae.Target.String())
+	out.WriteString(" " + ae.Operator + " ")
+	out.WriteString(ae.Value.String())
+	out.WriteString(token.RPAREN)
+
+	return out.String()
+}
+
 type IfExpression struct {
+	Location
 	Token             token.Token // The 'if' token
 	Condition         Expression
 	Consequence       *BlockStatement
@@ -220,6 +332,7 @@ func (ie *IfExpression) String() string {
 }
 
 type ElseIfExpression struct {
+	Location
 	Token       token.Token // The 'else if' token
 	Condition   Expression
 	Consequence *BlockStatement
@@ -239,9 +352,11 @@ func (ie *ElseIfExpression) String() string {
 }
 
 type CallExpression struct {
-	Token     token.Token // The '(' token
-	Function  Expression  // Identifier or FunctionLiteral
-	Arguments []Expression
+	Location
+	Token          token.Token // The '(' token
+	Function       Expression  // Identifier or FunctionLiteral
+	Arguments      []Expression
+	NamedArguments []*NamedArgument // Always after the positional arguments
 }
 
 func (ce *CallExpression) expressionNode()      {}
@@ -253,6 +368,9 @@ func (ce *CallExpression) String() string {
 	for _, a := range ce.Arguments {
 		args = append(args, a.String())
 	}
+	for _, a := range ce.NamedArguments {
+		args = append(args, a.String())
+	}
 
 	out.WriteString(ce.Function.String())
 	out.WriteString(token.LPAREN)
@@ -262,7 +380,18 @@ func (ce *CallExpression) String() string {
 	return out.String()
 }
 
+// NamedArgument is an argument passed by parameter name, as in f(y: 2)
+type NamedArgument struct {
+	Location
+	Name  *Identifier
+	Value Expression
+}
+
+func (na *NamedArgument) TokenLiteral() string { return na.Name.TokenLiteral() }
+func (na *NamedArgument) String() string       { return na.Name.String() + ": " + na.Value.String() }
+
 type Identifier struct {
+	Location
 	Token token.Token // the token.Identifier token
 	Value string
 }
@@ -272,6 +401,7 @@ func (i *Identifier) TokenLiteral() string { return i.Token.Literal }
 func (i *Identifier) String() string       { return i.Value }
 
 type Boolean struct {
+	Location
 	Token token.Token
 	Value bool
 }
@@ -281,6 +411,7 @@ func (b *Boolean) TokenLiteral() string { return b.Token.Literal }
 func (b *Boolean) String() string       { return b.Token.Literal }
 
 type IntegerLiteral struct {
+	Location
 	Token token.Token
 	Value int64
 }
@@ -289,7 +420,29 @@ func (il *IntegerLiteral) expressionNode()      {}
 func (il *IntegerLiteral) TokenLiteral() string { return il.Token.Literal }
 func (il *IntegerLiteral) String() string       { return il.Token.Literal }
 
+// BigIntegerLiteral is an integer literal too large for an int64
+type BigIntegerLiteral struct {
+	Location
+	Token token.Token
+	Value *big.Int
+}
+
+func (bl *BigIntegerLiteral) expressionNode()      {}
+func (bl *BigIntegerLiteral) TokenLiteral() string { return bl.Token.Literal }
+func (bl *BigIntegerLiteral) String() string       { return bl.Token.Literal }
+
+type FloatLiteral struct {
+	Location
+	Token token.Token
+	Value float64
+}
+
+func (fl *FloatLiteral) expressionNode()      {}
+func (fl *FloatLiteral) TokenLiteral() string { return fl.Token.Literal }
+func (fl *FloatLiteral) String() string       { return fl.Token.Literal }
+
 type StringLiteral struct {
+	Location
 	Token token.Token
 	Value string
 }
@@ -298,9 +451,40 @@ func (sl *StringLiteral) expressionNode()      {}
 func (sl *StringLiteral) TokenLiteral() string { return sl.Token.Literal }
 func (sl *StringLiteral) String() string       { return sl.Token.Literal }
 
+// InterpolatedString is a string literal with embedded expressions, such as
+// "Hello ${name}". Parts holds the literal text as *StringLiteral nodes and
+// the embedded expressions, in source order.
+type InterpolatedString struct {
+	Location
+	Token token.Token // The STRINGHEAD token
+	Parts []Expression
+}
+
+func (is *InterpolatedString) expressionNode()      {}
+func (is *InterpolatedString) TokenLiteral() string { return is.Token.Literal }
+func (is *InterpolatedString) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("\"")
+	for _, part := range is.Parts {
+		if literal, ok := part.(*StringLiteral); ok {
+			out.WriteString(literal.Value)
+			continue
+		}
+		out.WriteString("${" + part.String() + "}")
+	}
+	out.WriteString("\"")
+
+	return out.String()
+}
+
 type FunctionLiteral struct {
+	Location
 	Token      token.Token // The 'fn' token
+	Name       string      // Set when the function is bound with let, for error messages
 	Parameters []*Identifier
+	Defaults   []Expression // The default of each parameter, nil if it has none
+	Rest       *Identifier  // Collects any extra arguments, as in fn(first, ...rest)
 	Body       *BlockStatement
 }
 
@@ -310,8 +494,15 @@ func (fl *FunctionLiteral) String() string {
 	var out bytes.Buffer
 
 	var params []string
-	for _, p := range fl.Parameters {
-		params = append(params, p.String())
+	for i, p := range fl.Parameters {
+		if i < len(fl.Defaults) && fl.Defaults[i] != nil {
+			params = append(params, p.String()+" = "+fl.Defaults[i].String())
+		} else {
+			params = append(params, p.String())
+		}
+	}
+	if fl.Rest != nil {
+		params = append(params, token.ELLIPSIS+fl.Rest.String())
 	}
 
 	out.WriteString(fl.TokenLiteral())
@@ -324,6 +515,7 @@ func (fl *FunctionLiteral) String() string {
 }
 
 type ArrayLiteral struct {
+	Location
 	Token    token.Token // the '[' token
 	Elements []Expression
 }
@@ -346,6 +538,7 @@ func (al *ArrayLiteral) String() string {
 }
 
 type IndexExpression struct {
+	Location
 	Token token.Token // The [ token
 	Left  Expression
 	Index Expression
@@ -367,8 +560,12 @@ func (ie *IndexExpression) String() string {
 }
 
 type HashLiteral struct {
+	Location
 	Token token.Token // The '{' token
 	Pairs map[Expression]Expression
+	// Entries holds the keys of Pairs and any spread expressions in source
+	// order, so that later entries override earlier ones
+	Entries []Expression
 }
 
 func (hl *HashLiteral) expressionNode()      {}
@@ -377,8 +574,12 @@ func (hl *HashLiteral) String() string {
 	var out bytes.Buffer
 
 	var pairs []string
-	for key, value := range hl.Pairs {
-		pairs = append(pairs, key.String()+token.COLON+value.String())
+	for _, entry := range hl.Entries {
+		if value, ok := hl.Pairs[entry]; ok {
+			pairs = append(pairs, entry.String()+token.COLON+value.String())
+		} else {
+			pairs = append(pairs, entry.String())
+		}
 	}
 
 	out.WriteString(token.LBRACE)
@@ -388,7 +589,20 @@ func (hl *HashLiteral) String() string {
 	return out.String()
 }
 
+// SpreadExpression expands an array in a call or array literal, or a hash in
+// a hash literal, as in f(...args), [...a, ...b] or {...defaults, ...options}
+type SpreadExpression struct {
+	Location
+	Token token.Token // The '...' token
+	Value Expression
+}
+
+func (se *SpreadExpression) expressionNode()      {}
+func (se *SpreadExpression) TokenLiteral() string { return se.Token.Literal }
+func (se *SpreadExpression) String() string       { return se.Token.Literal + se.Value.String() }
+
 type NullValue struct {
+	Location
 	Token token.Token
 }
 
@@ -397,10 +611,11 @@ func (nv *NullValue) TokenLiteral() string { return nv.Token.Literal }
 func (nv *NullValue) String() string       { return nv.Token.Literal }
 
 type ForExpression struct {
+	Location
 	Token                token.Token // The 'for' token
-	Initialization       *LetStatement
+	Initialization       Statement
 	Condition            Expression
-	IncrementOrDecrement *LetStatement
+	IncrementOrDecrement Statement
 	Consequence          *BlockStatement
 }
 
@@ -421,7 +636,37 @@ func (fl *ForExpression) String() string {
 	return out.String()
 }
 
+// ForInExpression loops over the elements of a collection, as in
+// for (x in xs) { ... } or for (k, v in hash) { ... }
+type ForInExpression struct {
+	Location
+	Token       token.Token // The 'for' token
+	Key         *Identifier // The index or key variable, nil if only one variable is given
+	Value       *Identifier
+	Iterable    Expression
+	Consequence *BlockStatement
+}
+
+func (fi *ForInExpression) expressionNode()      {}
+func (fi *ForInExpression) TokenLiteral() string { return fi.Token.Literal }
+func (fi *ForInExpression) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("for (")
+	if fi.Key != nil {
+		out.WriteString(fi.Key.String() + ", ")
+	}
+	out.WriteString(fi.Value.String())
+	out.WriteString(" in ")
+	out.WriteString(fi.Iterable.String())
+	out.WriteString(") ")
+	out.WriteString(fi.Consequence.String())
+
+	return out.String()
+}
+
 type WhileExpression struct {
+	Location
 	Token       token.Token // The 'while' token
 	Condition   Expression
 	Consequence *BlockStatement
@@ -439,3 +684,180 @@ func (fl *WhileExpression) String() string {
 
 	return out.String()
 }
+
+// MatchExpression compares a value against the patterns of its arms in order
+// and evaluates the body of the first arm that matches
+type MatchExpression struct {
+	Location
+	Token   token.Token // The 'match' token
+	Subject Expression
+	Arms    []*MatchArm
+}
+
+func (me *MatchExpression) expressionNode()      {}
+func (me *MatchExpression) TokenLiteral() string { return me.Token.Literal }
+func (me *MatchExpression) String() string {
+	var out bytes.Buffer
+
+	var arms []string
+	for _, arm := range me.Arms {
+		arms = append(arms, arm.String())
+	}
+
+	out.WriteString("match (")
+	out.WriteString(me.Subject.String())
+	out.WriteString(") { ")
+	out.WriteString(strings.Join(arms, ", "))
+	out.WriteString(" }")
+
+	return out.String()
+}
+
+// MatchArm is a single pattern => body arm of a match expression
+type MatchArm struct {
+	Location
+	Token   token.Token // The '=>' token
+	Pattern Pattern
+	Guard   Expression // nil when the arm has no if guard
+	Body    *BlockStatement
+}
+
+func (ma *MatchArm) TokenLiteral() string { return ma.Token.Literal }
+func (ma *MatchArm) String() string {
+	var out bytes.Buffer
+
+	out.WriteString(ma.Pattern.String())
+	if ma.Guard != nil {
+		out.WriteString(" if " + ma.Guard.String())
+	}
+	out.WriteString(" => ")
+	out.WriteString(ma.Body.String())
+
+	return out.String()
+}
+
+// Patterns
+
+// WildcardPattern is _, which matches any value
+type WildcardPattern struct {
+	Location
+	Token token.Token // The '_' token
+}
+
+func (wp *WildcardPattern) patternNode()         {}
+func (wp *WildcardPattern) TokenLiteral() string { return wp.Token.Literal }
+func (wp *WildcardPattern) String() string       { return "_" }
+
+// LiteralPattern matches values equal to a literal, such as 1, -2.5, "circle",
+// true or null
+type LiteralPattern struct {
+	Location
+	Token token.Token // The first token of the literal
+	Value Expression
+}
+
+func (lp *LiteralPattern) patternNode()         {}
+func (lp *LiteralPattern) TokenLiteral() string { return lp.Token.Literal }
+func (lp *LiteralPattern) String() string {
+	if str, ok := lp.Value.(*StringLiteral); ok {
+		return strconv.Quote(str.Value)
+	}
+	return lp.Value.String()
+}
+
+// BindingPattern matches any value and binds it to a name
+type BindingPattern struct {
+	Location
+	Name *Identifier
+}
+
+func (bp *BindingPattern) patternNode()         {}
+func (bp *BindingPattern) TokenLiteral() string { return bp.Name.TokenLiteral() }
+func (bp *BindingPattern) String() string       { return bp.Name.String() }
+
+// ArrayPattern matches an array element by element. With a rest element, as
+// in [first, ..rest], it matches arrays with at least as many elements as
+// there are patterns, and binds the remaining elements to rest.
+type ArrayPattern struct {
+	Location
+	Token    token.Token // The '[' token
+	Elements []Pattern
+	HasRest  bool
+	Rest     *Identifier // nil for a rest element without a name, as in [first, ..]
+}
+
+func (ap *ArrayPattern) patternNode()         {}
+func (ap *ArrayPattern) TokenLiteral() string { return ap.Token.Literal }
+func (ap *ArrayPattern) String() string {
+	var elements []string
+	for _, el := range ap.Elements {
+		elements = append(elements, el.String())
+	}
+	if ap.HasRest && ap.Rest != nil {
+		elements = append(elements, ".."+ap.Rest.String())
+	} else if ap.HasRest {
+		elements = append(elements, "..")
+	}
+
+	return "[" + strings.Join(elements, ", ") + "]"
+}
+
+// HashPattern matches a hash that has all of the given keys, with values
+// matching their patterns. Other keys in the hash are ignored.
+type HashPattern struct {
+	Location
+	Token token.Token // The '{' token
+	Pairs []*HashPatternPair
+}
+
+// HashPatternPair is a single key: pattern entry of a hash pattern
+type HashPatternPair struct {
+	Key   Expression
+	Value Pattern
+}
+
+func (hp *HashPattern) patternNode()         {}
+func (hp *HashPattern) TokenLiteral() string { return hp.Token.Literal }
+func (hp *HashPattern) String() string {
+	var pairs []string
+	for _, pair := range hp.Pairs {
+		key := pair.Key.String()
+		if str, ok := pair.Key.(*StringLiteral); ok {
+			key = strconv.Quote(str.Value)
+		}
+		pairs = append(pairs, key+": "+pair.Value.String())
+	}
+
+	return "{" + strings.Join(pairs, ", ") + "}"
+}
+
+// DefaultPattern is an element of a destructuring pattern with a default, as
+// in let [a, b = 10] = xs; The default is used when the element is missing.
+type DefaultPattern struct {
+	Location
+	Pattern Pattern
+	Default Expression
+}
+
+func (dp *DefaultPattern) patternNode()         {}
+func (dp *DefaultPattern) TokenLiteral() string { return dp.Pattern.TokenLiteral() }
+func (dp *DefaultPattern) String() string {
+	return dp.Pattern.String() + " = " + dp.Default.String()
+}
+
+// AlternativePattern matches when any of its alternatives does, as in 1 | 2
+type AlternativePattern struct {
+	Location
+	Alternatives []Pattern
+}
+
+func (ap *AlternativePattern) patternNode()         {}
+func (ap *AlternativePattern) TokenLiteral() string { return ap.Alternatives[0].TokenLiteral() }
+func (ap *AlternativePattern) String() string {
+	var alternatives []string
+	for _, alternative := range ap.Alternatives {
+		alternatives = append(alternatives, alternative.String())
+	}
+
+	return strings.Join(alternatives, " | ")
+}
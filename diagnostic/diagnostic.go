@@ -0,0 +1,196 @@
+package diagnostic
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/anilang-official/AniLang/token"
+)
+
+type Severity int // How serious a diagnostic is (e.g. Error, Warning, Note)
+
+const (
+	Error Severity = iota
+	Warning
+	Note
+)
+
+func (s Severity) String() string {
+	switch s {
+	case Error:
+		return "error"
+	case Warning:
+		return "warning"
+	case Note:
+		return "note"
+	default:
+		return "unknown"
+	}
+}
+
+// Diagnostic is a problem found in the source, along with where it was found
+type Diagnostic struct {
+	Severity Severity
+	Message  string
+	File     string     // Name of the source file, empty if unknown
+	Span     token.Span // Source range the diagnostic points at
+	Hint     string     // Optional suggestion on how to fix the problem
+}
+
+// New returns an error diagnostic for the given span
+func New(file string, span token.Span, format string, a ...interface{}) *Diagnostic {
+	return &Diagnostic{
+		Severity: Error,
+		Message:  fmt.Sprintf(format, a...),
+		File:     file,
+		Span:     span,
+	}
+}
+
+// Error formats the diagnostic on a single line, e.g. "main.ani:3:14: error: ..."
+func (d *Diagnostic) Error() string {
+	return fmt.Sprintf("%s: %s: %s", d.location(), d.Severity, d.Message)
+}
+
+func (d *Diagnostic) location() string {
+	file := d.File
+	if file == "" {
+		file = "<input>"
+	}
+	if !d.Span.Start.IsValid() {
+		return file
+	}
+	return file + ":" + d.Span.Start.String()
+}
+
+// ANSI escape codes used when colour output is enabled
+const (
+	colorReset = "\033[0m"
+	colorBold  = "\033[1m"
+	colorRed   = "\033[31m"
+	colorYlw   = "\033[33m"
+	colorCyan  = "\033[36m"
+	colorBlue  = "\033[34m"
+)
+
+// Printer renders diagnostics together with the offending source line and a
+// caret underline pointing at the span
+type Printer struct {
+	out   io.Writer
+	lines []string
+	color bool
+}
+
+// NewPrinter returns a Printer writing to out. source is the text the
+// diagnostics were produced from.
+func NewPrinter(out io.Writer, source string, color bool) *Printer {
+	return &Printer{out: out, lines: strings.Split(source, "\n"), color: color}
+}
+
+// Print renders a single diagnostic
+func (p *Printer) Print(d *Diagnostic) {
+	var out strings.Builder
+
+	out.WriteString(p.paint(colorBold+severityColor(d.Severity), d.Severity.String()))
+	out.WriteString(p.paint(colorBold, ": "+d.Message))
+	out.WriteString("\n")
+
+	start := d.Span.Start
+	if !start.IsValid() || start.Line > len(p.lines) {
+		out.WriteString(p.paint(colorBlue, " --> ") + d.location() + "\n")
+		p.writeHint(&out, d, "")
+		io.WriteString(p.out, out.String())
+		return
+	}
+
+	line := strings.TrimRight(p.lines[start.Line-1], "\r")
+	number := fmt.Sprintf("%d", start.Line)
+	gutter := strings.Repeat(" ", len(number))
+
+	out.WriteString(gutter + p.paint(colorBlue, "--> ") + d.location() + "\n")
+	out.WriteString(gutter + p.paint(colorBlue, " |") + "\n")
+	out.WriteString(p.paint(colorBlue, number+" | ") + line + "\n")
+	out.WriteString(gutter + p.paint(colorBlue, " | ") + underline(line, d.Span, p.paint, severityColor(d.Severity)) + "\n")
+	p.writeHint(&out, d, gutter)
+
+	io.WriteString(p.out, out.String())
+}
+
+// PrintAll renders every diagnostic in order
+func (p *Printer) PrintAll(diagnostics []*Diagnostic) {
+	for _, d := range diagnostics {
+		p.Print(d)
+	}
+}
+
+func (p *Printer) writeHint(out *strings.Builder, d *Diagnostic, gutter string) {
+	if d.Hint == "" {
+		return
+	}
+	out.WriteString(gutter + p.paint(colorBlue, " = ") + p.paint(colorBold, "hint") + ": " + d.Hint + "\n")
+}
+
+func (p *Printer) paint(color, text string) string {
+	if !p.color {
+		return text
+	}
+	return color + text + colorReset
+}
+
+// underline builds the caret line for span, keeping tabs from the source line
+// so the carets stay aligned. Columns count characters, not bytes.
+func underline(line string, span token.Span, paint func(string, string) string, color string) string {
+	chars := []rune(line)
+	column := span.Start.Column - 1
+	if column > len(chars) {
+		column = len(chars)
+	}
+
+	width := 1
+	if span.End.Line == span.Start.Line && span.End.Column-span.Start.Column > 1 {
+		width = span.End.Column - span.Start.Column
+	} else if span.End.Line > span.Start.Line && len(chars)-column > 1 {
+		width = len(chars) - column
+	}
+
+	var padding strings.Builder
+	for i := 0; i < column; i++ {
+		if chars[i] == '\t' {
+			padding.WriteByte('\t')
+		} else {
+			padding.WriteByte(' ')
+		}
+	}
+
+	return padding.String() + paint(colorBold+color, strings.Repeat("^", width))
+}
+
+func severityColor(s Severity) string {
+	switch s {
+	case Warning:
+		return colorYlw
+	case Note:
+		return colorCyan
+	default:
+		return colorRed
+	}
+}
+
+// IsTerminal reports whether w is a terminal that colour output can be
+// written to. Setting the NO_COLOR environment variable disables colour.
+func IsTerminal(w io.Writer) bool {
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
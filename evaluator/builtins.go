@@ -2,6 +2,7 @@ package evaluator
 
 import (
 	"fmt"
+	"unicode/utf8"
 
 	"github.com/anilang-official/AniLang/object"
 )
@@ -9,20 +10,19 @@ import (
 var builtins = map[string]*object.Builtin{
 
 	"len": {
+		Name:   "len",
+		Params: []string{"value"},
 		Fn: func(args ...object.Object) object.Object {
-
-			if len(args) != 1 {
-				return newError("wrong number of arguments. got=%d, want=1",
-					len(args))
-			}
-
 			switch arg := args[0].(type) {
 
 			case *object.Array:
 				return &object.Integer{Value: int64(len(arg.Elements))}
 
 			case *object.String:
-				return &object.Integer{Value: int64(len(arg.Value))}
+				return &object.Integer{Value: int64(utf8.RuneCountInString(arg.Value))}
+
+			case *object.Range:
+				return &object.Integer{Value: arg.Len()}
 
 			default:
 				return newError("argument to `len` not supported, got %s",
@@ -32,13 +32,9 @@ var builtins = map[string]*object.Builtin{
 	},
 
 	"append": {
+		Name:   "append",
+		Params: []string{"collection", "value"},
 		Fn: func(args ...object.Object) object.Object {
-
-			if len(args) != 2 {
-				return newError("wrong number of arguments. got=%d, want=2",
-					len(args))
-			}
-
 			switch arg := args[0].(type) {
 
 			case *object.Array:
@@ -59,26 +55,23 @@ var builtins = map[string]*object.Builtin{
 	},
 
 	"charAt": {
+		Name:   "charAt",
+		Params: []string{"string", "index"},
 		Fn: func(args ...object.Object) object.Object {
-
-			if len(args) != 2 {
-				return newError("wrong number of arguments. got=%d, want=2",
-					len(args))
-			}
-
 			switch arg := args[0].(type) {
 
 			case *object.String:
-				if args[1].Type() != object.INTEGER_OBJ {
+				if !isInteger(args[1]) {
 					return newError("2nd argument to `charAt` must be INTEGER, got %s",
 						args[1].Type())
 				}
 
-				index := args[1].(*object.Integer).Value
-				if index < 0 || index > int64(len(arg.Value)-1) {
+				chars := []rune(arg.Value)
+				index := indexValue(args[1])
+				if index < 0 || index > int64(len(chars)-1) {
 					return newError("index out of range")
 				}
-				return &object.String{Value: string(arg.Value[index])}
+				return &object.String{Value: string(chars[index])}
 
 			default:
 				return newError("argument to `charAt` not supported, got %s",
@@ -88,14 +81,45 @@ var builtins = map[string]*object.Builtin{
 		},
 	},
 
-	"first": {
+	"byteLen": {
+		Name:   "byteLen",
+		Params: []string{"string"},
+		Fn: func(args ...object.Object) object.Object {
+			if args[0].Type() != object.STRING_OBJ {
+				return newError("argument to `byteLen` must be STRING, got %s",
+					args[0].Type())
+			}
+
+			return &object.Integer{Value: int64(len(args[0].(*object.String).Value))}
+		},
+	},
+
+	"byteAt": {
+		Name:   "byteAt",
+		Params: []string{"string", "index"},
 		Fn: func(args ...object.Object) object.Object {
+			if args[0].Type() != object.STRING_OBJ {
+				return newError("argument to `byteAt` must be STRING, got %s",
+					args[0].Type())
+			}
+			if !isInteger(args[1]) {
+				return newError("2nd argument to `byteAt` must be INTEGER, got %s",
+					args[1].Type())
+			}
 
-			if len(args) != 1 {
-				return newError("wrong number of arguments. got=%d, want=1",
-					len(args))
+			str := args[0].(*object.String).Value
+			index := indexValue(args[1])
+			if index < 0 || index > int64(len(str)-1) {
+				return newError("index out of range")
 			}
+			return &object.Integer{Value: int64(str[index])}
+		},
+	},
 
+	"first": {
+		Name:   "first",
+		Params: []string{"array"},
+		Fn: func(args ...object.Object) object.Object {
 			if args[0].Type() != object.ARRAY_OBJ {
 				return newError("argument to `first` must be ARRAY, got %s",
 					args[0].Type())
@@ -111,13 +135,9 @@ var builtins = map[string]*object.Builtin{
 	},
 
 	"last": {
+		Name:   "last",
+		Params: []string{"array"},
 		Fn: func(args ...object.Object) object.Object {
-
-			if len(args) != 1 {
-				return newError("wrong number of arguments. got=%d, want=1",
-					len(args))
-			}
-
 			if args[0].Type() != object.ARRAY_OBJ {
 				return newError("argument to `last` must be ARRAY, got %s",
 					args[0].Type())
@@ -134,11 +154,9 @@ var builtins = map[string]*object.Builtin{
 	},
 
 	"push": {
+		Name:   "push",
+		Params: []string{"array", "value"},
 		Fn: func(args ...object.Object) object.Object {
-			if len(args) != 2 {
-				return newError("wrong number of arguments. got=%d, want=2",
-					len(args))
-			}
 			if args[0].Type() != object.ARRAY_OBJ {
 				return newError("argument to `push` must be ARRAY, got %s",
 					args[0].Type())
@@ -152,7 +170,42 @@ var builtins = map[string]*object.Builtin{
 		},
 	},
 
+	"toArray": {
+		Name:   "toArray",
+		Params: []string{"value"},
+		Fn: func(args ...object.Object) object.Object {
+			switch arg := args[0].(type) {
+
+			case *object.Range:
+				elements, err := rangeElements(arg)
+				if err != nil {
+					return err
+				}
+				return &object.Array{Elements: elements}
+
+			case *object.String:
+				elements := []object.Object{}
+				for _, ch := range arg.Value {
+					elements = append(elements, &object.String{Value: string(ch)})
+				}
+				return &object.Array{Elements: elements}
+
+			case *object.Array:
+				elements := make([]object.Object, len(arg.Elements))
+				copy(elements, arg.Elements)
+				return &object.Array{Elements: elements}
+
+			default:
+				return newError("argument to `toArray` not supported, got %s",
+					args[0].Type())
+			}
+		},
+	},
+
 	"puts": {
+		Name:     "puts",
+		Params:   []string{"values"},
+		Variadic: true,
 		Fn: func(args ...object.Object) object.Object {
 			for _, arg := range args {
 				fmt.Println(arg.Inspect())
@@ -162,6 +215,9 @@ var builtins = map[string]*object.Builtin{
 	},
 
 	"print": {
+		Name:     "print",
+		Params:   []string{"values"},
+		Variadic: true,
 		Fn: func(args ...object.Object) object.Object {
 			for _, arg := range args {
 				fmt.Print(arg.Inspect())
@@ -171,6 +227,9 @@ var builtins = map[string]*object.Builtin{
 	},
 
 	"println": {
+		Name:     "println",
+		Params:   []string{"values"},
+		Variadic: true,
 		Fn: func(args ...object.Object) object.Object {
 			for _, arg := range args {
 				fmt.Println(arg.Inspect())
@@ -179,3 +238,12 @@ var builtins = map[string]*object.Builtin{
 		},
 	},
 }
+
+// indexValue returns an integer index argument as an int64. A big integer is
+// too large to be a valid index, so it gives -1, which is always out of range.
+func indexValue(index object.Object) int64 {
+	if integer, ok := index.(*object.Integer); ok {
+		return integer.Value
+	}
+	return -1
+}
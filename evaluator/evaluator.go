@@ -2,6 +2,10 @@ package evaluator
 
 import (
 	"fmt"
+	"math"
+	"math/big"
+	"sort"
+	"strings"
 
 	"github.com/anilang-official/AniLang/ast"
 	"github.com/anilang-official/AniLang/object"
@@ -33,9 +37,15 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 	case *ast.ForExpression:
 		return evalForExpression(node, env)
 
+	case *ast.ForInExpression:
+		return evalForInExpression(node, env)
+
 	case *ast.WhileExpression:
 		return evalWhileExpression(node, env)
 
+	case *ast.MatchExpression:
+		return evalMatchExpression(node, env)
+
 	case *ast.LetStatement:
 		return evalLetStatement(node, env)
 
@@ -58,7 +68,14 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 	case *ast.FunctionLiteral:
 		params := node.Parameters
 		body := node.Body
-		return &object.Function{Parameters: params, Env: env, Body: body}
+		return &object.Function{
+			Name:       node.Name,
+			Parameters: params,
+			Defaults:   node.Defaults,
+			Rest:       node.Rest,
+			Env:        env,
+			Body:       body,
+		}
 
 	case *ast.CallExpression:
 		function := Eval(node.Function, env)
@@ -69,7 +86,11 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 		if len(args) == 1 && isError(args[0]) {
 			return args[0]
 		}
-		return applyFunction(function, args)
+		named, err := evalNamedArguments(node.NamedArguments, env)
+		if err != nil {
+			return err
+		}
+		return applyFunction(function, args, named)
 
 	case *ast.IndexExpression:
 		left := Eval(node.Left, env)
@@ -86,9 +107,18 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 	case *ast.IntegerLiteral:
 		return &object.Integer{Value: node.Value}
 
+	case *ast.BigIntegerLiteral:
+		return &object.BigInteger{Value: node.Value}
+
+	case *ast.FloatLiteral:
+		return &object.Float{Value: node.Value}
+
 	case *ast.StringLiteral:
 		return &object.String{Value: node.Value}
 
+	case *ast.InterpolatedString:
+		return evalInterpolatedString(node, env)
+
 	case *ast.ArrayLiteral:
 		elements := evalExpressions(node.Elements, env)
 		if len(elements) == 1 && isError(elements[0]) {
@@ -120,6 +150,15 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 		}
 		return evalInfixExpression(node.Operator, left, right)
 
+	case *ast.LogicalExpression:
+		return evalLogicalExpression(node, env)
+
+	case *ast.RangeExpression:
+		return evalRangeExpression(node, env)
+
+	case *ast.AssignExpression:
+		return evalAssignExpression(node, env)
+
 	case *ast.Identifier:
 		return evalIdentifier(node, env)
 
@@ -148,7 +187,14 @@ func evalProgram(program *ast.Program, env *object.Environment) object.Object {
 	return result
 }
 
+// evalBlockStatement runs a block in its own scope, so variables declared in
+// an if or loop body do not leak into the enclosing scope
 func evalBlockStatement(block *ast.BlockStatement, env *object.Environment) object.Object {
+	return evalStatements(block, object.NewEnclosedEnvironment(env))
+}
+
+// evalStatements runs the statements of a block directly in env
+func evalStatements(block *ast.BlockStatement, env *object.Environment) object.Object {
 	var result object.Object
 	for _, statement := range block.Statements {
 		result = Eval(statement, env)
@@ -168,6 +214,8 @@ func evalPrefixExpression(operator string, right object.Object) object.Object {
 		return evalBangOperatorExpression(right)
 	case "-":
 		return evalMinusPrefixOperatorExpression(right)
+	case "~":
+		return evalBitwiseNotOperatorExpression(right)
 	default:
 		return newError("unknown operator: %s%s", operator, right.Type())
 	}
@@ -187,11 +235,30 @@ func evalBangOperatorExpression(right object.Object) object.Object {
 }
 
 func evalMinusPrefixOperatorExpression(right object.Object) object.Object {
-	if right.Type() != object.INTEGER_OBJ {
+	switch right := right.(type) {
+	case *object.Integer:
+		if right.Value == math.MinInt64 {
+			return newInteger(new(big.Int).Neg(big.NewInt(right.Value)))
+		}
+		return &object.Integer{Value: -right.Value}
+	case *object.BigInteger:
+		return newInteger(new(big.Int).Neg(right.Value))
+	case *object.Float:
+		return &object.Float{Value: -right.Value}
+	default:
 		return newError("unknown operator: -%s", right.Type())
 	}
-	value := right.(*object.Integer).Value
-	return &object.Integer{Value: -value}
+}
+
+func evalBitwiseNotOperatorExpression(right object.Object) object.Object {
+	switch right := right.(type) {
+	case *object.Integer:
+		return &object.Integer{Value: ^right.Value}
+	case *object.BigInteger:
+		return newInteger(new(big.Int).Not(right.Value))
+	default:
+		return newError("unknown operator: ~%s", right.Type())
+	}
 }
 
 func evalInfixExpression(
@@ -199,8 +266,16 @@ func evalInfixExpression(
 	left, right object.Object,
 ) object.Object {
 	switch {
+	case operator == "in":
+		return evalInExpression(left, right)
 	case left.Type() == object.INTEGER_OBJ && right.Type() == object.INTEGER_OBJ:
 		return evalIntegerInfixExpression(operator, left, right)
+	case isInteger(left) && isInteger(right):
+		// At least one side is a big integer
+		return evalBigIntegerInfixExpression(operator, left, right)
+	case isNumber(left) && isNumber(right):
+		// At least one side is a float, so the integer side is promoted
+		return evalFloatInfixExpression(operator, left, right)
 	case left.Type() == object.STRING_OBJ && right.Type() == object.STRING_OBJ:
 		return evalStringInfixExpression(operator, left, right)
 	case operator == "==":
@@ -216,48 +291,156 @@ func evalInfixExpression(
 	}
 }
 
-func evalIntegerInfixExpression(
+// evalInExpression tests for membership: of a value in an array or range, of
+// a key in a hash, or of a substring in a string
+func evalInExpression(left, right object.Object) object.Object {
+	switch right := right.(type) {
+	case *object.Range:
+		n, ok := left.(*object.Integer)
+		return nativeBoolToBooleanObject(ok && right.Contains(n.Value))
+	case *object.Array:
+		for _, element := range right.Elements {
+			if evalInfixExpression("==", left, element) == TRUE {
+				return TRUE
+			}
+		}
+		return FALSE
+	case *object.Hash:
+		key, ok := left.(object.Hashable)
+		if !ok {
+			return newError("unusable as hash key: %s", left.Type())
+		}
+		_, ok = right.Pairs[key.HashKey()]
+		return nativeBoolToBooleanObject(ok)
+	case *object.String:
+		str, ok := left.(*object.String)
+		if !ok {
+			return newError("type mismatch: %s in %s", left.Type(), right.Type())
+		}
+		return nativeBoolToBooleanObject(strings.Contains(right.Value, str.Value))
+	default:
+		return newError("unknown operator: %s in %s", left.Type(), right.Type())
+	}
+}
+
+func evalRangeExpression(node *ast.RangeExpression, env *object.Environment) object.Object {
+	var bounds [3]int64
+	bounds[2] = 1 // The default step
+
+	for i, exp := range []ast.Expression{node.From, node.To, node.Step} {
+		if exp == nil {
+			continue
+		}
+		val := Eval(exp, env)
+		if isError(val) {
+			return val
+		}
+		integer, ok := val.(*object.Integer)
+		if !ok {
+			return newError("range bounds and step must be INTEGER, got %s", val.Type())
+		}
+		bounds[i] = integer.Value
+	}
+
+	if bounds[2] == 0 {
+		return newError("range step cannot be zero")
+	}
+	rng := &object.Range{Start: bounds[0], End: bounds[1], Step: bounds[2], Inclusive: node.Inclusive}
+	if rng.TooLong() {
+		return newError("range too long: %s", rng.Inspect())
+	}
+	return rng
+}
+
+// maxRangeElements caps how many values can be taken out of a range at once,
+// since ranges are lazy and can be far larger than memory
+const maxRangeElements = 1 << 22
+
+// rangeElements returns the values of a range as Integers
+func rangeElements(rng *object.Range) ([]object.Object, *object.Error) {
+	if rng.Len() > maxRangeElements {
+		return nil, newError("range too large to expand: %s has %d values, the limit is %d",
+			rng.Inspect(), rng.Len(), maxRangeElements)
+	}
+
+	elements := make([]object.Object, rng.Len())
+	for i := range elements {
+		elements[i] = &object.Integer{Value: rng.At(int64(i))}
+	}
+	return elements, nil
+}
+
+// evalLogicalExpression evaluates && and ||, short-circuiting so the right
+// side is only evaluated when the left side does not decide the result
+func evalLogicalExpression(node *ast.LogicalExpression, env *object.Environment) object.Object {
+	left := Eval(node.Left, env)
+	if isError(left) {
+		return left
+	}
+
+	switch {
+	case node.Operator == "&&" && !isTruthy(left):
+		return FALSE
+	case node.Operator == "||" && isTruthy(left):
+		return TRUE
+	}
+
+	right := Eval(node.Right, env)
+	if isError(right) {
+		return right
+	}
+	return nativeBoolToBooleanObject(isTruthy(right))
+}
+
+// evalInterpolatedString joins the literal parts of the string with the
+// Inspect output of each embedded expression
+func evalInterpolatedString(node *ast.InterpolatedString, env *object.Environment) object.Object {
+	var out strings.Builder
+
+	parts := evalExpressions(node.Parts, env)
+	if len(parts) == 1 && isError(parts[0]) {
+		return parts[0]
+	}
+	for _, part := range parts {
+		// Expressions with no value, such as a call to a function whose body
+		// ends in a let, interpolate as null
+		if part == nil {
+			part = NULL
+		}
+		out.WriteString(part.Inspect())
+	}
+
+	return &object.String{Value: out.String()}
+}
+
+func evalStringInfixExpression(
 	operator string,
 	left, right object.Object,
 ) object.Object {
-	leftVal := left.(*object.Integer).Value
-	rightVal := right.(*object.Integer).Value
+	leftVal := left.(*object.String).Value
+	rightVal := right.(*object.String).Value
 	switch operator {
 	case "+":
-		return &object.Integer{Value: leftVal + rightVal}
-	case "-":
-		return &object.Integer{Value: leftVal - rightVal}
-	case "*":
-		return &object.Integer{Value: leftVal * rightVal}
-	case "/":
-		return &object.Integer{Value: leftVal / rightVal}
-	case "<":
-		return nativeBoolToBooleanObject(leftVal < rightVal)
-	case ">":
-		return nativeBoolToBooleanObject(leftVal > rightVal)
+		return &object.String{Value: leftVal + rightVal}
+	// Strings compare by value, and order lexicographically by code point
 	case "==":
 		return nativeBoolToBooleanObject(leftVal == rightVal)
 	case "!=":
 		return nativeBoolToBooleanObject(leftVal != rightVal)
+	case "<":
+		return nativeBoolToBooleanObject(leftVal < rightVal)
+	case ">":
+		return nativeBoolToBooleanObject(leftVal > rightVal)
+	case "<=":
+		return nativeBoolToBooleanObject(leftVal <= rightVal)
+	case ">=":
+		return nativeBoolToBooleanObject(leftVal >= rightVal)
 	default:
 		return newError("unknown operator: %s %s %s",
 			left.Type(), operator, right.Type())
 	}
 }
 
-func evalStringInfixExpression(
-	operator string,
-	left, right object.Object,
-) object.Object {
-	if operator != "+" {
-		return newError("unknown operator: %s %s %s",
-			left.Type(), operator, right.Type())
-	}
-	leftVal := left.(*object.String).Value
-	rightVal := right.(*object.String).Value
-	return &object.String{Value: leftVal + rightVal}
-}
-
 func evalLetStatement(let *ast.LetStatement, env *object.Environment) object.Object {
 	if let.Assignment.Type == token.INCREMENT || let.Assignment.Type == token.DECREMENT {
 		return evalIncrementDecrement(let, env)
@@ -268,103 +451,155 @@ func evalLetStatement(let *ast.LetStatement, env *object.Environment) object.Obj
 		return val
 	}
 
+	if let.Pattern != nil {
+		return destructure(let.Pattern, val, env)
+	}
+
 	if let.Assignment.Type == token.ASSIGN {
 		env.Set(let.Name.Value, val)
-	} else if let.Assignment.Type == token.PLUSEQUAL && val.Type() == object.INTEGER_OBJ {
-		obj, ok := env.Get(let.Name.Value)
-
-		if !ok {
-			env.Set(let.Name.Value, val)
-		} else {
-			env.Set(let.Name.Value, evalInfixExpression("+", obj, val))
-		}
+		return nil
+	}
 
-	} else if let.Assignment.Type == token.MINUSEQUAL && val.Type() == object.INTEGER_OBJ {
-		obj, ok := env.Get(let.Name.Value)
+	// Compound forms such as let x += 1 update the variable in the scope that
+	// defines it, so closures can update the variables they capture
+	obj, ok := env.Get(let.Name.Value)
+	if !ok {
+		env.Set(let.Name.Value, val)
+		return nil
+	}
 
-		if !ok {
-			env.Set(let.Name.Value, val)
-		} else {
-			env.Set(let.Name.Value, evalInfixExpression("-", obj, val))
-		}
+	result := evalCompoundOperator(let.Assignment.Literal, obj, val)
+	if isError(result) {
+		return result
+	}
+	env.Assign(let.Name.Value, result)
 
-	} else if let.Assignment.Type == token.MULTIPLYEQUAL && val.Type() == object.INTEGER_OBJ {
-		obj, ok := env.Get(let.Name.Value)
+	return nil
+}
 
-		if !ok {
-			env.Set(let.Name.Value, val)
-		} else {
-			env.Set(let.Name.Value, evalInfixExpression("*", obj, val))
-		}
+// evalCompoundOperator applies the operator of a compound assignment such as
+// += to the current value of the target and the assigned value. Both
+// let x += v and x += v go through here, so they accept the same operands.
+func evalCompoundOperator(operator string, current, val object.Object) object.Object {
+	return evalInfixExpression(strings.TrimSuffix(operator, "="), current, val)
+}
 
-	} else if let.Assignment.Type == token.DIVIDEEQUAL && val.Type() == object.INTEGER_OBJ {
-		obj, ok := env.Get(let.Name.Value)
+func evalIncrementDecrement(let *ast.LetStatement, env *object.Environment) object.Object {
+	obj, ok := env.Get(let.Name.Value)
+	if !ok {
+		return newError("unknown variable: %s", let.Name.Value)
+	}
 
-		if !ok {
-			env.Set(let.Name.Value, val)
-		} else {
-			env.Set(let.Name.Value, evalInfixExpression("/", obj, val))
-		}
+	if !isInteger(obj) {
+		return newError("unknown operator: %s %s", let.Assignment.Type, obj.Type())
+	}
 
-	} else if let.Assignment.Type == token.BITWISEANDEQUAL && val.Type() == object.INTEGER_OBJ {
-		obj, ok := env.Get(let.Name.Value)
+	one := &object.Integer{Value: 1}
+	if let.Assignment.Type == token.INCREMENT {
+		env.Assign(let.Name.Value, evalInfixExpression("+", obj, one))
+	} else {
+		env.Assign(let.Name.Value, evalInfixExpression("-", obj, one))
+	}
+	return nil
+}
 
-		if !ok {
-			env.Set(let.Name.Value, val)
-		} else {
-			env.Set(let.Name.Value, evalInfixExpression("&", obj, val))
-		}
+// evalAssignExpression assigns to a variable or to an element of an array or
+// hash, and returns the assigned value
+func evalAssignExpression(node *ast.AssignExpression, env *object.Environment) object.Object {
+	switch target := node.Target.(type) {
+	case *ast.Identifier:
+		return evalVariableAssignment(target.Value, node, env)
+	case *ast.IndexExpression:
+		return evalIndexAssignment(target, node, env)
+	default:
+		return newError("cannot assign to %s", node.Target.String())
+	}
+}
 
-	} else if let.Assignment.Type == token.BITWISEOREQUAL && val.Type() == object.INTEGER_OBJ {
-		obj, ok := env.Get(let.Name.Value)
+// evalVariableAssignment updates an existing variable in the scope that
+// defines it
+func evalVariableAssignment(name string, node *ast.AssignExpression, env *object.Environment) object.Object {
+	val := Eval(node.Value, env)
+	if isError(val) {
+		return val
+	}
 
+	if node.Operator != "=" {
+		current, ok := env.Get(name)
 		if !ok {
-			env.Set(let.Name.Value, val)
-		} else {
-			env.Set(let.Name.Value, evalInfixExpression("|", obj, val))
+			return newError("assignment to undeclared variable: %s", name)
+		}
+		val = evalCompoundOperator(node.Operator, current, val)
+		if isError(val) {
+			return val
 		}
+	}
 
-	} else if let.Assignment.Type == token.MODULOEQUAL && val.Type() == object.INTEGER_OBJ {
-		obj, ok := env.Get(let.Name.Value)
+	if _, ok := env.Assign(name, val); !ok {
+		return newError("assignment to undeclared variable: %s", name)
+	}
+	return val
+}
 
-		if !ok {
-			env.Set(let.Name.Value, val)
-		} else {
-			env.Set(let.Name.Value, evalInfixExpression("%", obj, val))
+// evalIndexAssignment updates an element of an array or hash in place.
+// Arrays can only be assigned within their bounds, while assigning to a new
+// hash key adds it.
+func evalIndexAssignment(target *ast.IndexExpression, node *ast.AssignExpression, env *object.Environment) object.Object {
+	left := Eval(target.Left, env)
+	if isError(left) {
+		return left
+	}
+	index := Eval(target.Index, env)
+	if isError(index) {
+		return index
+	}
+	val := Eval(node.Value, env)
+	if isError(val) {
+		return val
+	}
+
+	switch left := left.(type) {
+	case *object.Array:
+		idx, ok := index.(*object.Integer)
+		if !ok && !isInteger(index) {
+			return newError("array index must be INTEGER, got %s", index.Type())
+		}
+		if !ok || idx.Value < 0 || idx.Value >= int64(len(left.Elements)) {
+			return newError("index out of range: %s (length %d)", index.Inspect(), len(left.Elements))
 		}
 
-	} else if let.Assignment.Type == token.BITWISEXOREQUAL && val.Type() == object.INTEGER_OBJ {
-		obj, ok := env.Get(let.Name.Value)
+		if node.Operator != "=" {
+			val = evalCompoundOperator(node.Operator, left.Elements[idx.Value], val)
+			if isError(val) {
+				return val
+			}
+		}
+		left.Elements[idx.Value] = val
 
+	case *object.Hash:
+		key, ok := index.(object.Hashable)
 		if !ok {
-			env.Set(let.Name.Value, val)
-		} else {
-			env.Set(let.Name.Value, evalInfixExpression("^", obj, val))
+			return newError("unusable as hash key: %s", index.Type())
 		}
+		hashed := key.HashKey()
 
-	} else {
-		return newError("unknown operator: %s", let.Assignment.Type)
-	}
-
-	return nil
-}
-
-func evalIncrementDecrement(let *ast.LetStatement, env *object.Environment) object.Object {
-	obj, ok := env.Get(let.Name.Value)
-	if !ok {
-		return newError("unknown variable: %s", let.Name.Value)
-	}
+		if node.Operator != "=" {
+			pair, ok := left.Pairs[hashed]
+			if !ok {
+				return newError("key not found: %s", index.Inspect())
+			}
+			val = evalCompoundOperator(node.Operator, pair.Value, val)
+			if isError(val) {
+				return val
+			}
+		}
+		left.Pairs[hashed] = object.HashPair{Key: index, Value: val}
 
-	if obj.Type() != object.INTEGER_OBJ {
-		return newError("unknown operator: %s %s", let.Assignment.Type, obj.Type())
+	default:
+		return newError("index assignment not supported: %s", left.Type())
 	}
 
-	if let.Assignment.Type == token.INCREMENT {
-		env.Set(let.Name.Value, &object.Integer{Value: obj.(*object.Integer).Value + 1})
-	} else {
-		env.Set(let.Name.Value, &object.Integer{Value: obj.(*object.Integer).Value - 1})
-	}
-	return nil
+	return val
 }
 
 func evalIfExpression(ie *ast.IfExpression, env *object.Environment) object.Object {
@@ -396,19 +631,24 @@ func evalIfExpression(ie *ast.IfExpression, env *object.Environment) object.Obje
 	}
 }
 
+// evalForExpression runs a C-style for loop. The loop variables live in their
+// own scope, and every iteration gets a fresh copy of them, so closures created
+// in the body each capture the values of their own iteration.
 func evalForExpression(fe *ast.ForExpression, env *object.Environment) object.Object {
-	initialization := Eval(fe.Initialization, env)
+	loopEnv := object.NewEnclosedEnvironment(env)
+
+	initialization := Eval(fe.Initialization, loopEnv)
 	if isError(initialization) {
 		return initialization
 	}
 
-	condition := Eval(fe.Condition, env)
+	condition := Eval(fe.Condition, loopEnv)
 	if isError(condition) {
 		return condition
 	}
 
 	for isTruthy(condition) {
-		consequence := Eval(fe.Consequence, env)
+		consequence := Eval(fe.Consequence, loopEnv)
 		if isError(consequence) {
 			return consequence
 		}
@@ -416,13 +656,14 @@ func evalForExpression(fe *ast.ForExpression, env *object.Environment) object.Ob
 			break
 		}
 		if isReturn(consequence) {
-			return unwrapReturnValue(consequence)
+			return consequence
 		}
-		incrementordecrement := Eval(fe.IncrementOrDecrement, env)
+		loopEnv = loopEnv.Copy()
+		incrementordecrement := Eval(fe.IncrementOrDecrement, loopEnv)
 		if isError(incrementordecrement) {
 			return incrementordecrement
 		}
-		condition = Eval(fe.Condition, env)
+		condition = Eval(fe.Condition, loopEnv)
 		if isError(condition) {
 			return condition
 		}
@@ -431,6 +672,73 @@ func evalForExpression(fe *ast.ForExpression, env *object.Environment) object.Ob
 	return NULL
 }
 
+// evalForInExpression loops over an array, a range, a string (by character)
+// or a hash (ordered by key). With one loop variable it takes the element, the
+// character or the hash key; with two it takes the index or key and the value.
+func evalForInExpression(fe *ast.ForInExpression, env *object.Environment) object.Object {
+	iterable := Eval(fe.Iterable, env)
+	if isError(iterable) {
+		return iterable
+	}
+
+	// body runs one iteration with fresh loop variables and reports whether
+	// the loop should stop, along with the result of the loop if it should
+	body := func(key, value, single object.Object) (object.Object, bool) {
+		iterEnv := object.NewEnclosedEnvironment(env)
+		if fe.Key != nil {
+			iterEnv.Set(fe.Key.Value, key)
+			iterEnv.Set(fe.Value.Value, value)
+		} else {
+			iterEnv.Set(fe.Value.Value, single)
+		}
+
+		consequence := Eval(fe.Consequence, iterEnv)
+		switch {
+		case isError(consequence), isReturn(consequence):
+			return consequence, true
+		case isBreak(consequence):
+			return NULL, true
+		default:
+			return nil, false
+		}
+	}
+
+	switch iterable := iterable.(type) {
+	case *object.Array:
+		for i, element := range iterable.Elements {
+			if result, stop := body(&object.Integer{Value: int64(i)}, element, element); stop {
+				return result
+			}
+		}
+	case *object.String:
+		i := 0
+		for _, ch := range iterable.Value {
+			char := &object.String{Value: string(ch)}
+			if result, stop := body(&object.Integer{Value: int64(i)}, char, char); stop {
+				return result
+			}
+			i++
+		}
+	case *object.Range:
+		for i := int64(0); i < iterable.Len(); i++ {
+			value := &object.Integer{Value: iterable.At(i)}
+			if result, stop := body(&object.Integer{Value: i}, value, value); stop {
+				return result
+			}
+		}
+	case *object.Hash:
+		for _, pair := range sortedPairs(iterable) {
+			if result, stop := body(pair.Key, pair.Value, pair.Key); stop {
+				return result
+			}
+		}
+	default:
+		return newError("cannot iterate over %s", iterable.Type())
+	}
+
+	return NULL
+}
+
 func evalWhileExpression(we *ast.WhileExpression, env *object.Environment) object.Object {
 	condition := Eval(we.Condition, env)
 	if isError(condition) {
@@ -446,7 +754,7 @@ func evalWhileExpression(we *ast.WhileExpression, env *object.Environment) objec
 			break
 		}
 		if isReturn(consequence) {
-			return unwrapReturnValue(consequence)
+			return consequence
 		}
 		condition = Eval(we.Condition, env)
 		if isError(condition) {
@@ -516,6 +824,15 @@ func evalExpressions(
 ) []object.Object {
 	var result []object.Object
 	for _, e := range exps {
+		if spread, ok := e.(*ast.SpreadExpression); ok {
+			elements := evalSpreadExpression(spread, env)
+			if len(elements) == 1 && isError(elements[0]) {
+				return elements
+			}
+			result = append(result, elements...)
+			continue
+		}
+
 		evaluated := Eval(e, env)
 		if isError(evaluated) {
 			return []object.Object{evaluated}
@@ -525,15 +842,71 @@ func evalExpressions(
 	return result
 }
 
-func applyFunction(fn object.Object, args []object.Object) object.Object {
+// evalSpreadExpression returns the elements of the array or range being
+// spread into an array literal or a call
+func evalSpreadExpression(spread *ast.SpreadExpression, env *object.Environment) []object.Object {
+	value := Eval(spread.Value, env)
+	if isError(value) {
+		return []object.Object{value}
+	}
+
+	switch value := value.(type) {
+	case *object.Array:
+		return value.Elements
+	case *object.Range:
+		elements, err := rangeElements(value)
+		if err != nil {
+			return []object.Object{err}
+		}
+		return elements
+	default:
+		return []object.Object{newError("cannot spread %s, expected ARRAY or RANGE", value.Type())}
+	}
+}
+
+// namedArgument is the value of an argument passed by name, as in f(y: 2)
+type namedArgument struct {
+	name  string
+	value object.Object
+}
+
+func evalNamedArguments(
+	arguments []*ast.NamedArgument,
+	env *object.Environment,
+) ([]namedArgument, object.Object) {
+	var named []namedArgument
+
+	for _, argument := range arguments {
+		evaluated := Eval(argument.Value, env)
+		if isError(evaluated) {
+			return nil, evaluated
+		}
+		named = append(named, namedArgument{name: argument.Name.Value, value: evaluated})
+	}
+	return named, nil
+}
+
+func applyFunction(fn object.Object, args []object.Object, named []namedArgument) object.Object {
 	switch fn := fn.(type) {
 
 	case *object.Function:
-		extendedEnv := extendFunctionEnv(fn, args)
-		evaluated := Eval(fn.Body, extendedEnv)
+		if err := checkFunctionArity(fn, len(args), len(named)); err != nil {
+			return err
+		}
+		extendedEnv, err := extendFunctionEnv(fn, args, named)
+		if err != nil {
+			return err
+		}
+		evaluated := evalStatements(fn.Body, extendedEnv)
 		return unwrapReturnValue(evaluated)
 
 	case *object.Builtin:
+		if len(named) > 0 {
+			return newError("builtin functions do not take named arguments, got %s", named[0].name)
+		}
+		if err := checkBuiltinArity(fn, len(args)); err != nil {
+			return err
+		}
 		return fn.Fn(args...)
 
 	default:
@@ -541,15 +914,115 @@ func applyFunction(fn object.Object, args []object.Object) object.Object {
 	}
 }
 
+// checkFunctionArity reports an error when a function is called with too
+// many positional arguments, or with too few arguments for the parameters
+// that have no default
+func checkFunctionArity(fn *object.Function, positional, named int) *object.Error {
+	min := 0
+	for i := range fn.Parameters {
+		if i >= len(fn.Defaults) || fn.Defaults[i] == nil {
+			min++
+		}
+	}
+	max := len(fn.Parameters)
+	if fn.Rest != nil {
+		max = -1
+	}
+
+	if positional+named < min || (max >= 0 && positional > max) {
+		return arityError(fn.Signature(), min, max, positional+named)
+	}
+	return nil
+}
+
+func checkBuiltinArity(fn *object.Builtin, got int) *object.Error {
+	min, max := len(fn.Params), len(fn.Params)
+	if fn.Variadic {
+		min, max = len(fn.Params)-1, -1
+	}
+
+	if got < min || (max >= 0 && got > max) {
+		return arityError(fn.Signature(), min, max, got)
+	}
+	return nil
+}
+
+// arityError describes a call with the wrong number of arguments. A max
+// below zero means there is no upper limit.
+func arityError(signature string, min, max, got int) *object.Error {
+	var expected string
+	switch {
+	case max < 0:
+		expected = fmt.Sprintf("at least %d", min)
+	case min == max:
+		expected = fmt.Sprintf("%d", min)
+	default:
+		expected = fmt.Sprintf("%d to %d", min, max)
+	}
+
+	return newError("wrong number of arguments to %s: expected %s, got %d", signature, expected, got)
+}
+
+// extendFunctionEnv binds the parameters of fn, first to the positional
+// arguments, then to the named ones. Parameters left over take their default,
+// which is evaluated in the new scope so it can refer to earlier parameters.
 func extendFunctionEnv(
 	fn *object.Function,
 	args []object.Object,
-) *object.Environment {
+	named []namedArgument,
+) (*object.Environment, object.Object) {
 	env := object.NewEnclosedEnvironment(fn.Env)
+
+	bound := make([]bool, len(fn.Parameters))
+	for paramIdx := range fn.Parameters {
+		if paramIdx < len(args) {
+			env.Set(fn.Parameters[paramIdx].Value, args[paramIdx])
+			bound[paramIdx] = true
+		}
+	}
+
+	if fn.Rest != nil {
+		var rest []object.Object
+		if len(args) > len(fn.Parameters) {
+			rest = make([]object.Object, len(args)-len(fn.Parameters))
+			copy(rest, args[len(fn.Parameters):])
+		}
+		env.Set(fn.Rest.Value, &object.Array{Elements: rest})
+	}
+
+	for _, argument := range named {
+		paramIdx := -1
+		for i, param := range fn.Parameters {
+			if param.Value == argument.name {
+				paramIdx = i
+				break
+			}
+		}
+		if paramIdx < 0 {
+			return nil, newError("unknown argument %s to %s", argument.name, fn.Signature())
+		}
+		if bound[paramIdx] {
+			return nil, newError("duplicate argument %s to %s", argument.name, fn.Signature())
+		}
+		env.Set(argument.name, argument.value)
+		bound[paramIdx] = true
+	}
+
 	for paramIdx, param := range fn.Parameters {
-		env.Set(param.Value, args[paramIdx])
+		if bound[paramIdx] {
+			continue
+		}
+		if paramIdx >= len(fn.Defaults) || fn.Defaults[paramIdx] == nil {
+			return nil, newError("missing argument %s to %s", param.Value, fn.Signature())
+		}
+		value := Eval(fn.Defaults[paramIdx], env)
+		if isError(value) {
+			return nil, value
+		}
+		env.Set(param.Value, value)
 	}
-	return env
+
+	return env, nil
 }
 
 func unwrapReturnValue(obj object.Object) object.Object {
@@ -563,6 +1036,12 @@ func evalIndexExpression(left, index object.Object) object.Object {
 	switch {
 	case left.Type() == object.ARRAY_OBJ && index.Type() == object.INTEGER_OBJ:
 		return evalArrayIndexExpression(left, index)
+	case left.Type() == object.ARRAY_OBJ && index.Type() == object.BIG_INTEGER_OBJ:
+		return NULL // Always out of range
+	case left.Type() == object.RANGE_OBJ && index.Type() == object.INTEGER_OBJ:
+		return evalRangeIndexExpression(left, index)
+	case left.Type() == object.RANGE_OBJ && index.Type() == object.BIG_INTEGER_OBJ:
+		return NULL // Always out of range
 	case left.Type() == object.HASH_OBJ:
 		return evalHashIndexExpression(left, index)
 	default:
@@ -580,12 +1059,37 @@ func evalArrayIndexExpression(array, index object.Object) object.Object {
 	return arrayObject.Elements[idx]
 }
 
+func evalRangeIndexExpression(rng, index object.Object) object.Object {
+	rangeObject := rng.(*object.Range)
+	idx := index.(*object.Integer).Value
+	if idx < 0 || idx >= rangeObject.Len() {
+		return NULL
+	}
+	return &object.Integer{Value: rangeObject.At(idx)}
+}
+
 func evalHashLiteral(
 	node *ast.HashLiteral,
 	env *object.Environment,
 ) object.Object {
 	pairs := make(map[object.HashKey]object.HashPair)
-	for keyNode, valueNode := range node.Pairs {
+	for _, keyNode := range node.Entries {
+		if spread, ok := keyNode.(*ast.SpreadExpression); ok {
+			value := Eval(spread.Value, env)
+			if isError(value) {
+				return value
+			}
+			hash, ok := value.(*object.Hash)
+			if !ok {
+				return newError("cannot spread %s into a hash, expected HASH", value.Type())
+			}
+			for hashed, pair := range hash.Pairs {
+				pairs[hashed] = pair
+			}
+			continue
+		}
+
+		valueNode := node.Pairs[keyNode]
 		key := Eval(keyNode, env)
 		if isError(key) {
 			return key
@@ -616,3 +1120,41 @@ func evalHashIndexExpression(hash, index object.Object) object.Object {
 	}
 	return pair.Value
 }
+
+// sortedPairs returns the pairs of a hash ordered by key, so iterating over a
+// hash is deterministic. Booleans sort first, then numbers, then strings.
+func sortedPairs(hash *object.Hash) []object.HashPair {
+	pairs := make([]object.HashPair, 0, len(hash.Pairs))
+	for _, pair := range hash.Pairs {
+		pairs = append(pairs, pair)
+	}
+	sort.Slice(pairs, func(i, j int) bool {
+		return keyLess(pairs[i].Key, pairs[j].Key)
+	})
+	return pairs
+}
+
+func keyLess(a, b object.Object) bool {
+	if rankA, rankB := keyRank(a), keyRank(b); rankA != rankB {
+		return rankA < rankB
+	}
+	switch a := a.(type) {
+	case *object.Boolean:
+		return !a.Value && b.(*object.Boolean).Value
+	case *object.String:
+		return a.Value < b.(*object.String).Value
+	default:
+		return evalInfixExpression("<", a, b) == TRUE
+	}
+}
+
+func keyRank(key object.Object) int {
+	switch key.(type) {
+	case *object.Boolean:
+		return 0
+	case *object.String:
+		return 2
+	default:
+		return 1
+	}
+}
@@ -0,0 +1,317 @@
+package evaluator
+
+import (
+	"math"
+	"math/big"
+
+	"github.com/anilang-official/AniLang/object"
+)
+
+// Integer arithmetic is done on int64 values. When a result does not fit in
+// an int64 it is redone on big.Int and returned as an object.BigInteger, and
+// BigInteger results that fit in an int64 again are returned as an
+// object.Integer, so every integer has exactly one representation.
+//
+// Dividing integers with / gives an integer only when the division is exact,
+// and a Float otherwise. ~/ is the truncating integer division.
+
+func evalIntegerInfixExpression(
+	operator string,
+	left, right object.Object,
+) object.Object {
+	leftVal := left.(*object.Integer).Value
+	rightVal := right.(*object.Integer).Value
+	switch operator {
+	case "+":
+		result := leftVal + rightVal
+		if (leftVal^result)&(rightVal^result) < 0 {
+			return evalBigIntegerInfixExpression(operator, left, right)
+		}
+		return &object.Integer{Value: result}
+	case "-":
+		result := leftVal - rightVal
+		if (leftVal^rightVal)&(leftVal^result) < 0 {
+			return evalBigIntegerInfixExpression(operator, left, right)
+		}
+		return &object.Integer{Value: result}
+	case "*":
+		if leftVal == 0 || rightVal == 0 {
+			return &object.Integer{Value: 0}
+		}
+		result := leftVal * rightVal
+		if result/rightVal != leftVal || (leftVal == -1 && rightVal == math.MinInt64) ||
+			(rightVal == -1 && leftVal == math.MinInt64) {
+			return evalBigIntegerInfixExpression(operator, left, right)
+		}
+		return &object.Integer{Value: result}
+	case "/", "~/":
+		if rightVal == 0 {
+			return newError("division by zero")
+		}
+		if operator == "/" && leftVal%rightVal != 0 {
+			return &object.Float{Value: float64(leftVal) / float64(rightVal)}
+		}
+		if leftVal == math.MinInt64 && rightVal == -1 {
+			return evalBigIntegerInfixExpression(operator, left, right)
+		}
+		return &object.Integer{Value: leftVal / rightVal}
+	case "%":
+		if rightVal == 0 {
+			return newError("division by zero")
+		}
+		return &object.Integer{Value: leftVal % rightVal}
+	case "&":
+		return &object.Integer{Value: leftVal & rightVal}
+	case "|":
+		return &object.Integer{Value: leftVal | rightVal}
+	case "^":
+		return &object.Integer{Value: leftVal ^ rightVal}
+	case "<<", ">>":
+		return evalShiftExpression(operator, left, right)
+	case "**":
+		return evalPowerExpression(left, right)
+	case "<":
+		return nativeBoolToBooleanObject(leftVal < rightVal)
+	case ">":
+		return nativeBoolToBooleanObject(leftVal > rightVal)
+	case "<=":
+		return nativeBoolToBooleanObject(leftVal <= rightVal)
+	case ">=":
+		return nativeBoolToBooleanObject(leftVal >= rightVal)
+	case "==":
+		return nativeBoolToBooleanObject(leftVal == rightVal)
+	case "!=":
+		return nativeBoolToBooleanObject(leftVal != rightVal)
+	default:
+		return newError("unknown operator: %s %s %s",
+			left.Type(), operator, right.Type())
+	}
+}
+
+func evalBigIntegerInfixExpression(
+	operator string,
+	left, right object.Object,
+) object.Object {
+	leftVal := toBigInt(left)
+	rightVal := toBigInt(right)
+	switch operator {
+	case "+":
+		return newInteger(new(big.Int).Add(leftVal, rightVal))
+	case "-":
+		return newInteger(new(big.Int).Sub(leftVal, rightVal))
+	case "*":
+		return newInteger(new(big.Int).Mul(leftVal, rightVal))
+	case "/", "~/":
+		if rightVal.Sign() == 0 {
+			return newError("division by zero")
+		}
+		quotient, remainder := new(big.Int).QuoRem(leftVal, rightVal, new(big.Int))
+		if operator == "/" && remainder.Sign() != 0 {
+			value, _ := new(big.Float).Quo(new(big.Float).SetInt(leftVal), new(big.Float).SetInt(rightVal)).Float64()
+			return &object.Float{Value: value}
+		}
+		return newInteger(quotient)
+	case "%":
+		if rightVal.Sign() == 0 {
+			return newError("division by zero")
+		}
+		return newInteger(new(big.Int).Rem(leftVal, rightVal))
+	case "&":
+		return newInteger(new(big.Int).And(leftVal, rightVal))
+	case "|":
+		return newInteger(new(big.Int).Or(leftVal, rightVal))
+	case "^":
+		return newInteger(new(big.Int).Xor(leftVal, rightVal))
+	case "<<", ">>":
+		return evalShiftExpression(operator, left, right)
+	case "**":
+		return evalPowerExpression(left, right)
+	case "<":
+		return nativeBoolToBooleanObject(leftVal.Cmp(rightVal) < 0)
+	case ">":
+		return nativeBoolToBooleanObject(leftVal.Cmp(rightVal) > 0)
+	case "<=":
+		return nativeBoolToBooleanObject(leftVal.Cmp(rightVal) <= 0)
+	case ">=":
+		return nativeBoolToBooleanObject(leftVal.Cmp(rightVal) >= 0)
+	case "==":
+		return nativeBoolToBooleanObject(leftVal.Cmp(rightVal) == 0)
+	case "!=":
+		return nativeBoolToBooleanObject(leftVal.Cmp(rightVal) != 0)
+	default:
+		return newError("unknown operator: %s %s %s",
+			left.Type(), operator, right.Type())
+	}
+}
+
+func evalFloatInfixExpression(
+	operator string,
+	left, right object.Object,
+) object.Object {
+	leftVal := toFloat(left)
+	rightVal := toFloat(right)
+	switch operator {
+	case "+":
+		return &object.Float{Value: leftVal + rightVal}
+	case "-":
+		return &object.Float{Value: leftVal - rightVal}
+	case "*":
+		return &object.Float{Value: leftVal * rightVal}
+	case "/":
+		if rightVal == 0 {
+			return newError("division by zero")
+		}
+		return &object.Float{Value: leftVal / rightVal}
+	case "~/":
+		if rightVal == 0 {
+			return newError("division by zero")
+		}
+		return floatToInteger(math.Trunc(leftVal / rightVal))
+	case "%":
+		if rightVal == 0 {
+			return newError("division by zero")
+		}
+		return &object.Float{Value: math.Mod(leftVal, rightVal)}
+	case "**":
+		return &object.Float{Value: math.Pow(leftVal, rightVal)}
+	case "<":
+		return nativeBoolToBooleanObject(leftVal < rightVal)
+	case ">":
+		return nativeBoolToBooleanObject(leftVal > rightVal)
+	case "<=":
+		return nativeBoolToBooleanObject(leftVal <= rightVal)
+	case ">=":
+		return nativeBoolToBooleanObject(leftVal >= rightVal)
+	case "==":
+		return nativeBoolToBooleanObject(leftVal == rightVal)
+	case "!=":
+		return nativeBoolToBooleanObject(leftVal != rightVal)
+	default:
+		return newError("unknown operator: %s %s %s",
+			left.Type(), operator, right.Type())
+	}
+}
+
+// maxIntegerBits caps the size of the integers that << and ** can produce, so
+// that a single expression cannot exhaust memory or run for minutes
+const maxIntegerBits = 1 << 20
+
+// evalShiftExpression shifts an integer of any size. Right shifts are
+// arithmetic, and left shifts that overflow an int64 give a BigInteger.
+func evalShiftExpression(operator string, left, right object.Object) object.Object {
+	count := toBigInt(right)
+	if count.Sign() < 0 {
+		return newError("negative shift count: %s", count)
+	}
+	if !count.IsUint64() || count.Uint64() > maxIntegerBits {
+		return newError("shift count too large: %s", count)
+	}
+	n := uint(count.Uint64())
+	if operator == "<<" && toBigInt(left).BitLen()+int(n) > maxIntegerBits {
+		return newError("integer too large: result of << would exceed %d bits", maxIntegerBits)
+	}
+
+	if left, ok := left.(*object.Integer); ok {
+		switch {
+		case operator == ">>" && n >= 64:
+			return &object.Integer{Value: left.Value >> 63}
+		case operator == ">>":
+			return &object.Integer{Value: left.Value >> n}
+		case n < 64 && (left.Value<<n)>>n == left.Value:
+			return &object.Integer{Value: left.Value << n}
+		}
+	}
+
+	if operator == "<<" {
+		return newInteger(new(big.Int).Lsh(toBigInt(left), n))
+	}
+	return newInteger(new(big.Int).Rsh(toBigInt(left), n))
+}
+
+// evalPowerExpression raises an integer of any size to an integer power.
+// Negative exponents give a Float, since the result is usually fractional.
+func evalPowerExpression(left, right object.Object) object.Object {
+	exponent := toBigInt(right)
+	if exponent.Sign() < 0 {
+		return &object.Float{Value: math.Pow(toFloat(left), toFloat(right))}
+	}
+
+	// The result has at most exponent * BitLen(base) bits. Bases 0, 1 and -1
+	// have a BitLen of at most 1 and never grow.
+	base := toBigInt(left)
+	if base.BitLen() > 1 {
+		bits := new(big.Int).Mul(exponent, big.NewInt(int64(base.BitLen())))
+		if bits.Cmp(big.NewInt(maxIntegerBits)) > 0 {
+			return newError("integer too large: result of ** would exceed %d bits", maxIntegerBits)
+		}
+	}
+	return newInteger(new(big.Int).Exp(base, exponent, nil))
+}
+
+// newInteger returns value as an Integer if it fits in an int64, or as a
+// BigInteger otherwise
+func newInteger(value *big.Int) object.Object {
+	if value.IsInt64() {
+		return &object.Integer{Value: value.Int64()}
+	}
+	return &object.BigInteger{Value: value}
+}
+
+// floatToInteger converts a whole float to an Integer or a BigInteger
+func floatToInteger(value float64) object.Object {
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return newError("cannot convert %g to an integer", value)
+	}
+	if value >= math.MinInt64 && value < math.MaxInt64 {
+		return &object.Integer{Value: int64(value)}
+	}
+	result, _ := big.NewFloat(value).Int(nil)
+	return newInteger(result)
+}
+
+// isInteger reports whether obj is an integer of any size
+func isInteger(obj object.Object) bool {
+	switch obj.(type) {
+	case *object.Integer, *object.BigInteger:
+		return true
+	default:
+		return false
+	}
+}
+
+// isNumber reports whether obj is an integer or a float
+func isNumber(obj object.Object) bool {
+	switch obj.(type) {
+	case *object.Integer, *object.BigInteger, *object.Float:
+		return true
+	default:
+		return false
+	}
+}
+
+// toBigInt converts an integer of any size to a big.Int
+func toBigInt(obj object.Object) *big.Int {
+	switch obj := obj.(type) {
+	case *object.Integer:
+		return big.NewInt(obj.Value)
+	case *object.BigInteger:
+		return obj.Value
+	default:
+		return new(big.Int)
+	}
+}
+
+// toFloat converts a number to a float64
+func toFloat(obj object.Object) float64 {
+	switch obj := obj.(type) {
+	case *object.Integer:
+		return float64(obj.Value)
+	case *object.BigInteger:
+		value, _ := new(big.Float).SetInt(obj.Value).Float64()
+		return value
+	case *object.Float:
+		return obj.Value
+	default:
+		return 0
+	}
+}
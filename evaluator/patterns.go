@@ -0,0 +1,211 @@
+package evaluator
+
+import (
+	"github.com/anilang-official/AniLang/ast"
+	"github.com/anilang-official/AniLang/object"
+)
+
+// evalMatchExpression tries each arm in order and evaluates the body of the
+// first arm whose pattern matches the subject and whose guard, if any, is
+// truthy. Names bound by a pattern are only visible in its guard and body.
+func evalMatchExpression(me *ast.MatchExpression, env *object.Environment) object.Object {
+	subject := Eval(me.Subject, env)
+	if isError(subject) {
+		return subject
+	}
+
+	for _, arm := range me.Arms {
+		armEnv := object.NewEnclosedEnvironment(env)
+		if !matchPattern(arm.Pattern, subject, armEnv) {
+			continue
+		}
+
+		if arm.Guard != nil {
+			guard := Eval(arm.Guard, armEnv)
+			if isError(guard) {
+				return guard
+			}
+			if !isTruthy(guard) {
+				continue
+			}
+		}
+
+		result := evalStatements(arm.Body, armEnv)
+		if result == nil {
+			return NULL
+		}
+		return result
+	}
+
+	return newError("no match arm matches %s", subject.Inspect())
+}
+
+// matchPattern reports whether value matches pattern, binding the names the
+// pattern captures in env as it goes
+func matchPattern(pattern ast.Pattern, value object.Object, env *object.Environment) bool {
+	switch pattern := pattern.(type) {
+	case *ast.WildcardPattern:
+		return true
+
+	case *ast.BindingPattern:
+		env.Set(pattern.Name.Value, value)
+		return true
+
+	case *ast.LiteralPattern:
+		literal := Eval(pattern.Value, env)
+		return evalInfixExpression("==", literal, value) == TRUE
+
+	case *ast.AlternativePattern:
+		// Each alternative binds into a scratch scope, so the names bound by
+		// an alternative that fails part way do not leak into the arm
+		for _, alternative := range pattern.Alternatives {
+			scratch := object.NewEnclosedEnvironment(env)
+			if matchPattern(alternative, value, scratch) {
+				env.Merge(scratch)
+				return true
+			}
+		}
+		return false
+
+	case *ast.ArrayPattern:
+		array, ok := value.(*object.Array)
+		if !ok {
+			return false
+		}
+		if len(array.Elements) < len(pattern.Elements) ||
+			(!pattern.HasRest && len(array.Elements) != len(pattern.Elements)) {
+			return false
+		}
+		for i, element := range pattern.Elements {
+			if !matchPattern(element, array.Elements[i], env) {
+				return false
+			}
+		}
+		if pattern.Rest != nil {
+			rest := make([]object.Object, len(array.Elements)-len(pattern.Elements))
+			copy(rest, array.Elements[len(pattern.Elements):])
+			env.Set(pattern.Rest.Value, &object.Array{Elements: rest})
+		}
+		return true
+
+	case *ast.HashPattern:
+		hash, ok := value.(*object.Hash)
+		if !ok {
+			return false
+		}
+		for _, pair := range pattern.Pairs {
+			key, ok := Eval(pair.Key, env).(object.Hashable)
+			if !ok {
+				return false
+			}
+			entry, ok := hash.Pairs[key.HashKey()]
+			if !ok || !matchPattern(pair.Value, entry.Value, env) {
+				return false
+			}
+		}
+		return true
+	}
+
+	return false
+}
+
+// destructure binds the names in the pattern of a destructuring let to the
+// parts of value they correspond to. It returns an error when value does not
+// have the shape the pattern expects, and nil otherwise.
+func destructure(pattern ast.Pattern, value object.Object, env *object.Environment) object.Object {
+	switch pattern := pattern.(type) {
+	case *ast.WildcardPattern:
+		return nil
+
+	case *ast.BindingPattern:
+		env.Set(pattern.Name.Value, value)
+		return nil
+
+	case *ast.DefaultPattern:
+		return destructure(pattern.Pattern, value, env)
+
+	case *ast.ArrayPattern:
+		array, ok := value.(*object.Array)
+		if !ok {
+			return newError("cannot destructure %s as an array", value.Type())
+		}
+
+		// Trailing elements with defaults may be missing
+		required := len(pattern.Elements)
+		for required > 0 {
+			if _, ok := pattern.Elements[required-1].(*ast.DefaultPattern); !ok {
+				break
+			}
+			required--
+		}
+
+		length := len(array.Elements)
+		switch {
+		case length < required && (pattern.HasRest || required < len(pattern.Elements)):
+			return newError("cannot destructure array of length %d: expected at least %d elements", length, required)
+		case length < required || (!pattern.HasRest && length > len(pattern.Elements)):
+			return newError("cannot destructure array of length %d: expected %d elements", length, len(pattern.Elements))
+		}
+
+		for i, element := range pattern.Elements {
+			var err object.Object
+			if i < length {
+				err = destructure(element, array.Elements[i], env)
+			} else {
+				err = destructureDefault(element.(*ast.DefaultPattern), env)
+			}
+			if err != nil {
+				return err
+			}
+		}
+
+		if pattern.Rest != nil {
+			var rest []object.Object
+			if length > len(pattern.Elements) {
+				rest = make([]object.Object, length-len(pattern.Elements))
+				copy(rest, array.Elements[len(pattern.Elements):])
+			}
+			env.Set(pattern.Rest.Value, &object.Array{Elements: rest})
+		}
+		return nil
+
+	case *ast.HashPattern:
+		hash, ok := value.(*object.Hash)
+		if !ok {
+			return newError("cannot destructure %s as a hash", value.Type())
+		}
+
+		for _, pair := range pattern.Pairs {
+			key := Eval(pair.Key, env)
+			hashKey, ok := key.(object.Hashable)
+			if !ok {
+				return newError("unusable as hash key: %s", key.Type())
+			}
+
+			var err object.Object
+			if entry, ok := hash.Pairs[hashKey.HashKey()]; ok {
+				err = destructure(pair.Value, entry.Value, env)
+			} else if withDefault, ok := pair.Value.(*ast.DefaultPattern); ok {
+				err = destructureDefault(withDefault, env)
+			} else {
+				err = newError("cannot destructure hash: key not found: %s", key.Inspect())
+			}
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	return newError("cannot destructure with pattern %s", pattern.String())
+}
+
+// destructureDefault binds a missing element to its default. Defaults are
+// evaluated in the scope being bound, so they can refer to earlier elements.
+func destructureDefault(pattern *ast.DefaultPattern, env *object.Environment) object.Object {
+	value := Eval(pattern.Default, env)
+	if isError(value) {
+		return value
+	}
+	return destructure(pattern.Pattern, value, env)
+}
@@ -1,35 +1,90 @@
 package lexer
 
 import (
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/anilang-official/AniLang/diagnostic"
 	"github.com/anilang-official/AniLang/token"
 )
 
 type Lexer struct {
+	file         string // Name of the file being lexed, empty for interactive input
 	input        string // Input string to lex
 	position     int    // Current position in input (points to current char)
 	nextPosition int    // Current reading position in input (after current char)
-	ch           byte   // Current char under examination
+	ch           rune   // Current char under examination
+	line         int    // Line of the current char (1-based)
+	column       int    // Column of the current char (1-based)
+	errors       []*diagnostic.Diagnostic
+
+	// Brace depth of each string interpolation being lexed, innermost last. A
+	// '}' at depth 0 ends the interpolation and resumes the enclosing string.
+	interpolations []int
 }
 
 // New returns a new Lexer instance
 func New(input string) *Lexer {
-	l := &Lexer{input: input}
+	l := &Lexer{input: input, line: 1}
 	l.readChar() // Read the first char in the input
 	return l
 }
 
-// NextToken returns the next token in the input string
+// NewFile returns a new Lexer instance for the contents of the named file
+func NewFile(file string, input string) *Lexer {
+	l := New(input)
+	l.file = file
+	return l
+}
+
+// File returns the name of the file being lexed
+func (l *Lexer) File() string {
+	return l.file
+}
+
+// Errors returns the problems found in the input so far, such as unterminated
+// comments
+func (l *Lexer) Errors() []*diagnostic.Diagnostic {
+	return l.errors
+}
+
+// NextToken returns the next token in the input string, annotated with the
+// source positions it was read from
 func (l *Lexer) NextToken() token.Token {
-	var tok token.Token
-	l.skipWhitespace()
+	leading := l.readTrivia()
+
+	start := l.currentPosition()
+	tok := l.readToken()
+	tok.Pos = start
+	tok.End = l.currentPosition()
+	tok.Leading = leading
 
-	l.skipWhitespace()
+	return tok
+}
+
+// errorAt records a lexer error covering the input from start to the current char
+func (l *Lexer) errorAt(start token.Position, format string, a ...interface{}) {
+	span := token.Span{Start: start, End: l.currentPosition()}
+	l.errors = append(l.errors, diagnostic.New(l.file, span, format, a...))
+}
+
+// currentPosition returns the source position of the current char
+func (l *Lexer) currentPosition() token.Position {
+	return token.Position{Offset: l.position, Line: l.line, Column: l.column}
+}
+
+// readToken reads the token starting at the current char
+func (l *Lexer) readToken() token.Token {
+	var tok token.Token
 
 	switch l.ch {
 	case '=':
 		tok = l.extraTokenCheck(
 			[]token.Token{
 				{Type: token.EQUAL, Literal: "="},
+				{Type: token.ARROW, Literal: ">"},
 			},
 			newToken(token.ASSIGN, l.ch),
 		)
@@ -71,6 +126,13 @@ func (l *Lexer) NextToken() token.Token {
 			},
 			newToken(token.SLASH, l.ch),
 		)
+	case '~':
+		tok = l.extraTokenCheck(
+			[]token.Token{
+				{Type: token.INTDIVIDE, Literal: "/"},
+			},
+			newToken(token.BITWISENOT, l.ch),
+		)
 	case '<':
 		tok = l.extraTokenCheck(
 			[]token.Token{
@@ -87,6 +149,20 @@ func (l *Lexer) NextToken() token.Token {
 			},
 			newToken(token.GREATERTHAN, l.ch),
 		)
+	case '.':
+		if l.peekChar() != '.' {
+			tok = newToken(token.ILLEGAL, l.ch)
+			break
+		}
+		l.readChar()
+		tok = token.Token{Type: token.DOTDOT, Literal: ".."}
+		if l.peekChar() == '<' {
+			l.readChar()
+			tok = token.Token{Type: token.DOTDOTLESS, Literal: "..<"}
+		} else if l.peekChar() == '.' {
+			l.readChar()
+			tok = token.Token{Type: token.ELLIPSIS, Literal: "..."}
+		}
 	case ',':
 		tok = newToken(token.COMMA, l.ch)
 	case ';':
@@ -128,16 +204,34 @@ func (l *Lexer) NextToken() token.Token {
 	case ')':
 		tok = newToken(token.RPAREN, l.ch)
 	case '{':
+		if n := len(l.interpolations); n > 0 {
+			l.interpolations[n-1]++
+		}
 		tok = newToken(token.LBRACE, l.ch)
 	case '}':
+		if n := len(l.interpolations); n > 0 {
+			if l.interpolations[n-1] == 0 {
+				l.interpolations = l.interpolations[:n-1]
+				tok.Type, tok.Literal = l.readStringPart(token.STRINGMID, token.STRINGTAIL)
+				break
+			}
+			l.interpolations[n-1]--
+		}
 		tok = newToken(token.RBRACE, l.ch)
 	case '[':
 		tok = newToken(token.LBRACKET, l.ch)
 	case ']':
 		tok = newToken(token.RBRACKET, l.ch)
 	case '"':
+		if strings.HasPrefix(l.input[l.position:], `"""`) {
+			tok.Type = token.STRING
+			tok.Literal = l.readTripleQuotedString()
+			break
+		}
+		tok.Type, tok.Literal = l.readStringPart(token.STRINGHEAD, token.STRING)
+	case '`':
 		tok.Type = token.STRING
-		tok.Literal = l.readString()
+		tok.Literal = l.readRawString()
 	case 0:
 		tok.Literal = ""
 		tok.Type = token.EOF
@@ -147,8 +241,7 @@ func (l *Lexer) NextToken() token.Token {
 			tok.Type = token.LookupIdentifierType(tok.Literal) // Check if the identifier is a keyword
 			return tok
 		} else if isDigit(l.ch) {
-			tok.Type = token.INT
-			tok.Literal = l.readNumber()
+			tok.Type, tok.Literal = l.readNumber()
 			return tok
 		} else {
 			tok = newToken(token.ILLEGAL, l.ch)
@@ -173,43 +266,320 @@ func (l *Lexer) extraTokenCheck(types []token.Token, _default token.Token) token
 }
 
 // newToken returns a new token.Token instance
-func newToken(tokenType token.TokenType, ch byte) token.Token {
+func newToken(tokenType token.TokenType, ch rune) token.Token {
 	return token.Token{Type: tokenType, Literal: string(ch)}
 }
 
+// readChar decodes the next UTF-8 character in the input. Columns count
+// characters, while positions are byte offsets into the input.
 func (l *Lexer) readChar() {
-	l.ch = l.peekChar()
+	if l.nextPosition > len(l.input) {
+		return // Already at the end of the input
+	}
+	if l.ch == '\n' {
+		l.line++
+		l.column = 0
+	}
 	l.position = l.nextPosition
-	l.nextPosition += 1
+	if l.position >= len(l.input) {
+		l.ch = 0
+		l.nextPosition = l.position + 1
+	} else {
+		ch, size := utf8.DecodeRuneInString(l.input[l.position:])
+		l.ch = ch
+		l.nextPosition = l.position + size
+	}
+	l.column++
 }
 
-func (l *Lexer) readString() string {
-	pos := l.position + 1
-	for {
-		l.readChar()
-		// continue if l.ch == \"
+// readStringPart reads a double-quoted string literal, or the rest of one
+// after an interpolation, and returns its value with the escape sequences
+// decoded. A part that ends at "${" is returned as the interpolated type and
+// the lexer then reads the embedded expression as ordinary tokens; a part that
+// ends at the closing quote is returned as the closing type. Invalid escapes
+// and a missing closing quote are reported as errors.
+func (l *Lexer) readStringPart(interpolated, closing token.TokenType) (token.TokenType, string) {
+	start := l.currentPosition()
+	var out strings.Builder
+
+	l.readChar() // Skip the opening '"' or the '}' ending the interpolation
+	for l.ch != '"' {
+		if l.ch == 0 {
+			l.errorAt(start, "unterminated string literal")
+			break
+		}
+		if l.ch == '$' && l.peekChar() == '{' {
+			l.readChar() // Leave the '{' to be skipped like the closing quote
+			l.interpolations = append(l.interpolations, 0)
+			return interpolated, out.String()
+		}
 		if l.ch == '\\' {
-			l.readChar()
-			if l.ch == '"' {
-				continue
-			}
+			l.readEscape(&out)
+			continue
 		}
+		out.WriteRune(l.ch)
+		l.readChar()
+	}
+	return closing, out.String()
+}
+
+// readRawString reads a backtick string literal. Its contents are taken as
+// written: there are no escape sequences and it may span several lines.
+func (l *Lexer) readRawString() string {
+	start := l.currentPosition()
 
-		if l.ch == '"' || l.ch == 0 {
+	l.readChar() // Skip the opening '`'
+	pos := l.position
+	for l.ch != '`' {
+		if l.ch == 0 {
+			l.errorAt(start, "unterminated raw string literal")
 			break
 		}
+		l.readChar()
 	}
 	return l.input[pos:l.position]
 }
 
-func (l *Lexer) readNumber() string {
+// readTripleQuotedString reads a """ string literal. Like a raw string it has
+// no escape sequences and may span several lines, and its value is dedented so
+// it can be indented along with the surrounding code.
+func (l *Lexer) readTripleQuotedString() string {
+	start := l.currentPosition()
+
+	for i := 0; i < 3; i++ {
+		l.readChar() // Skip the opening '"""'
+	}
 	pos := l.position
-	for isDigit(l.ch) {
+	for !strings.HasPrefix(l.input[l.position:], `"""`) {
+		if l.ch == 0 {
+			l.errorAt(start, "unterminated triple-quoted string literal")
+			return dedent(l.input[pos:l.position])
+		}
+		l.readChar()
+	}
+	text := l.input[pos:l.position]
+
+	l.readChar()
+	l.readChar() // Leave the last '"' to be skipped like a closing quote
+	return dedent(text)
+}
+
+// dedent removes a blank first and last line from a triple-quoted string, then
+// strips the leading whitespace shared by all of its non-blank lines
+func dedent(text string) string {
+	lines := strings.Split(text, "\n")
+	if len(lines) > 1 && strings.TrimSpace(lines[0]) == "" {
+		lines = lines[1:]
+	}
+	if len(lines) > 1 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	indent := -1
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		n := len(line) - len(strings.TrimLeft(line, " \t"))
+		if indent < 0 || n < indent {
+			indent = n
+		}
+	}
+
+	for i, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			lines[i] = ""
+		} else {
+			lines[i] = line[indent:]
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// simpleEscapes maps the character after a '\' to the byte it stands for
+var simpleEscapes = map[rune]rune{
+	'n':  '\n',
+	't':  '\t',
+	'r':  '\r',
+	'"':  '"',
+	'\'': '\'',
+	'\\': '\\',
+	'0':  0,
+	'a':  '\a',
+	'b':  '\b',
+	'f':  '\f',
+	'v':  '\v',
+	'?':  '?',
+	'$':  '$',
+}
+
+// readEscape decodes the escape sequence starting at the current '\' and
+// writes it to out. Besides the simple escapes it supports \xNN and \u{N...},
+// both of which name a Unicode code point in hexadecimal.
+func (l *Lexer) readEscape(out *strings.Builder) {
+	start := l.currentPosition()
+	l.readChar() // Skip the '\'
+
+	ch := l.ch
+	if ch == 0 {
+		return // The missing closing quote is reported by readStringPart
+	}
+	l.readChar()
+
+	if decoded, ok := simpleEscapes[ch]; ok {
+		out.WriteRune(decoded)
+		return
+	}
+
+	switch ch {
+	case 'x':
+		digits := l.readHexDigits(2)
+		if len(digits) != 2 {
+			l.errorAt(start, "\\x escape must be followed by two hexadecimal digits")
+			return
+		}
+		value, _ := strconv.ParseUint(digits, 16, 8)
+		out.WriteRune(rune(value))
+	case 'u':
+		if l.ch != '{' {
+			l.errorAt(start, "\\u escape must have the form \\u{XXXX}")
+			return
+		}
+		l.readChar()
+		digits := l.readHexDigits(6)
+		if digits == "" || l.ch != '}' {
+			l.errorAt(start, "\\u escape must have the form \\u{XXXX}")
+			return
+		}
+		l.readChar()
+		value, _ := strconv.ParseUint(digits, 16, 32)
+		if !utf8.ValidRune(rune(value)) {
+			l.errorAt(start, "invalid Unicode code point U+%s", strings.ToUpper(digits))
+			return
+		}
+		out.WriteRune(rune(value))
+	default:
+		l.errorAt(start, "unknown escape sequence '\\%c'", ch)
+	}
+}
+
+// readHexDigits reads up to max hexadecimal digits
+func (l *Lexer) readHexDigits(max int) string {
+	pos := l.position
+	for l.position-pos < max && digitValue(l.ch) < 16 {
 		l.readChar()
 	}
 	return l.input[pos:l.position]
 }
 
+// readNumber reads an integer or float literal. Integers may use a 0x, 0o or
+// 0b base prefix, floats may have a fraction and an exponent, and digits may
+// be separated by underscores (1_000_000). Malformed literals are reported as
+// errors and returned as ILLEGAL tokens.
+func (l *Lexer) readNumber() (token.TokenType, string) {
+	start := l.currentPosition()
+
+	if l.ch == '0' && strings.ContainsRune("xXoObB", l.peekChar()) {
+		return l.readPrefixedInteger(start)
+	}
+
+	var tokenType token.TokenType = token.INT
+	l.readDecimalDigits()
+
+	if l.ch == '.' && isDigit(l.peekChar()) {
+		tokenType = token.FLOAT
+		l.readChar() // Skip the '.'
+		l.readDecimalDigits()
+	}
+
+	if l.ch == 'e' || l.ch == 'E' {
+		tokenType = token.FLOAT
+		l.readChar()
+		if l.ch == '+' || l.ch == '-' {
+			l.readChar()
+		}
+		if !isDigit(l.ch) {
+			l.skipAlphanumeric()
+			return l.numberError(start, "exponent has no digits")
+		}
+		l.readDecimalDigits()
+	}
+
+	if isLetter(l.ch) {
+		ch := l.ch
+		l.skipAlphanumeric()
+		return l.numberError(start, "invalid character %q in number literal", ch)
+	}
+
+	literal := l.input[start.Offset:l.position]
+	if !validUnderscores(literal, 10) {
+		return l.numberError(start, "'_' must separate successive digits")
+	}
+	// 010 used to be read as octal, so rather than quietly giving it a new
+	// value, leading zeros are an error
+	if tokenType == token.INT && len(literal) > 1 && literal[0] == '0' {
+		digits := strings.TrimLeft(literal, "0_")
+		if digits == "" {
+			digits = "0"
+		}
+		return l.numberError(start, "leading zero in decimal literal %s; use 0o%s for octal", literal, digits)
+	}
+	return tokenType, literal
+}
+
+// readPrefixedInteger reads an integer literal with a 0x, 0o or 0b prefix
+func (l *Lexer) readPrefixedInteger(start token.Position) (token.TokenType, string) {
+	var base int
+	var name string
+	switch l.peekChar() {
+	case 'x', 'X':
+		base, name = 16, "hexadecimal"
+	case 'o', 'O':
+		base, name = 8, "octal"
+	default:
+		base, name = 2, "binary"
+	}
+
+	l.readChar()
+	l.readChar()
+	// Read letters too, so a malformed literal like 0xZZ is reported as a whole
+	l.skipAlphanumeric()
+
+	literal := l.input[start.Offset:l.position]
+	digits := literal[2:]
+	if strings.Trim(digits, "_") == "" {
+		return l.numberError(start, "%s literal has no digits", name)
+	}
+	for _, ch := range digits {
+		if ch != '_' && digitValue(ch) >= base {
+			return l.numberError(start, "invalid digit %q in %s literal", ch, name)
+		}
+	}
+	if !validUnderscores(literal, base) {
+		return l.numberError(start, "'_' must separate successive digits")
+	}
+	return token.INT, literal
+}
+
+func (l *Lexer) readDecimalDigits() {
+	for isDigit(l.ch) || l.ch == '_' {
+		l.readChar()
+	}
+}
+
+func (l *Lexer) skipAlphanumeric() {
+	for isLetter(l.ch) || isDigit(l.ch) {
+		l.readChar()
+	}
+}
+
+// numberError reports a malformed number literal that starts at start and
+// returns it as an ILLEGAL token
+func (l *Lexer) numberError(start token.Position, format string, a ...interface{}) (token.TokenType, string) {
+	l.errorAt(start, format, a...)
+	return token.ILLEGAL, l.input[start.Offset:l.position]
+}
+
 func (l *Lexer) readIdentifier() string {
 	pos := l.position
 	for isLetter(l.ch) {
@@ -218,26 +588,122 @@ func (l *Lexer) readIdentifier() string {
 	return l.input[pos:l.position]
 }
 
-// isLetter returns true if the byte is a letter
-func isLetter(ch byte) bool {
-	return 'a' <= ch && ch <= 'z' || 'A' <= ch && ch <= 'Z' || ch == '_'
+// isLetter returns true if the char is a Unicode letter or '_'
+func isLetter(ch rune) bool {
+	return unicode.IsLetter(ch) || ch == '_'
 }
 
-// isDigit returns true if the byte is a digit
-func isDigit(ch byte) bool {
+// isDigit returns true if the char is an ASCII digit
+func isDigit(ch rune) bool {
 	return '0' <= ch && ch <= '9'
 }
 
+// digitValue returns the value of ch as a digit in bases up to 16, or 16 if
+// it is not a digit
+func digitValue(ch rune) int {
+	switch {
+	case '0' <= ch && ch <= '9':
+		return int(ch - '0')
+	case 'a' <= ch && ch <= 'f':
+		return int(ch - 'a' + 10)
+	case 'A' <= ch && ch <= 'F':
+		return int(ch - 'A' + 10)
+	default:
+		return 16
+	}
+}
+
+// validUnderscores reports whether every '_' in a number literal sits between
+// two digits of the given base (or between a base prefix and a digit)
+func validUnderscores(literal string, base int) bool {
+	for i := 0; i < len(literal); i++ {
+		if literal[i] != '_' {
+			continue
+		}
+		if i == 0 || i == len(literal)-1 {
+			return false
+		}
+		before, after := rune(literal[i-1]), rune(literal[i+1])
+		if digitValue(before) >= base && !(i == 2 && base != 10) {
+			return false
+		}
+		if digitValue(after) >= base {
+			return false
+		}
+	}
+	return true
+}
+
+// readTrivia skips whitespace and returns the comments in front of the next token
+func (l *Lexer) readTrivia() []token.Trivia {
+	var trivia []token.Trivia
+	for {
+		l.skipWhitespace()
+
+		if l.ch == '/' && l.peekChar() == '/' {
+			trivia = append(trivia, l.readLineComment())
+		} else if l.ch == '/' && l.peekChar() == '*' {
+			trivia = append(trivia, l.readBlockComment())
+		} else {
+			return trivia
+		}
+	}
+}
+
+// readLineComment reads a // comment up to, but not including, the end of the line
+func (l *Lexer) readLineComment() token.Trivia {
+	start := l.currentPosition()
+	for l.ch != '\n' && l.ch != 0 {
+		l.readChar()
+	}
+	return token.Trivia{
+		Type:    token.LINECOMMENT,
+		Literal: l.input[start.Offset:l.position],
+		Pos:     start,
+		End:     l.currentPosition(),
+	}
+}
+
+// readBlockComment reads a /* */ comment. Block comments nest, so
+// /* a /* b */ c */ is a single comment.
+func (l *Lexer) readBlockComment() token.Trivia {
+	start := l.currentPosition()
+	depth := 0
+	for {
+		if l.ch == 0 {
+			l.errorAt(start, "unterminated block comment")
+			break
+		}
+		if l.ch == '/' && l.peekChar() == '*' {
+			depth++
+			l.readChar()
+		} else if l.ch == '*' && l.peekChar() == '/' {
+			depth--
+			l.readChar()
+		}
+		l.readChar()
+		if depth == 0 {
+			break
+		}
+	}
+	return token.Trivia{
+		Type:    token.BLOCKCOMMENT,
+		Literal: l.input[start.Offset:l.position],
+		Pos:     start,
+		End:     l.currentPosition(),
+	}
+}
+
 func (l *Lexer) skipWhitespace() {
 	for l.ch == ' ' || l.ch == '\t' || l.ch == '\n' || l.ch == '\r' {
 		l.readChar()
 	}
 }
 
-func (l *Lexer) peekChar() byte {
+func (l *Lexer) peekChar() rune {
 	if l.nextPosition >= len(l.input) {
 		return 0
-	} else {
-		return l.input[l.nextPosition]
 	}
+	ch, _ := utf8.DecodeRuneInString(l.input[l.nextPosition:])
+	return ch
 }
@@ -29,3 +29,32 @@ func (e *Environment) Set(name string, val Object) Object {
 	e.store[name] = val
 	return val
 }
+
+// Assign updates an existing variable in the scope that defines it, looking
+// through the outer scopes. It returns false if the variable is not defined.
+func (e *Environment) Assign(name string, val Object) (Object, bool) {
+	for env := e; env != nil; env = env.outer {
+		if _, ok := env.store[name]; ok {
+			env.store[name] = val
+			return val, true
+		}
+	}
+	return nil, false
+}
+
+// Copy returns a new environment with the same outer scope and a copy of e's
+// own variables. Loops use it to give each iteration fresh loop variables.
+func (e *Environment) Copy() *Environment {
+	env := NewEnclosedEnvironment(e.outer)
+	for name, val := range e.store {
+		env.store[name] = val
+	}
+	return env
+}
+
+// Merge copies the variables defined in other's own scope into e
+func (e *Environment) Merge(other *Environment) {
+	for name, val := range other.store {
+		e.store[name] = val
+	}
+}
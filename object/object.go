@@ -4,6 +4,9 @@ import (
 	"bytes"
 	"fmt"
 	"hash/fnv"
+	"math"
+	"math/big"
+	"strconv"
 	"strings"
 
 	"github.com/anilang-official/AniLang/ast"
@@ -16,6 +19,8 @@ type BuiltinFunction func(args ...Object) Object
 
 const (
 	INTEGER_OBJ      = "INTEGER"
+	BIG_INTEGER_OBJ  = "BIG_INTEGER"
+	FLOAT_OBJ        = "FLOAT"
 	BOOLEAN_OBJ      = "BOOLEAN"
 	NULL_OBJ         = "NULL"
 	RETURN_VALUE_OBJ = "RETURN_VALUE"
@@ -24,6 +29,7 @@ const (
 	STRING_OBJ       = "STRING"
 	BUILTIN_OBJ      = "BUILTIN"
 	ARRAY_OBJ        = "ARRAY"
+	RANGE_OBJ        = "RANGE"
 	HASH_OBJ         = "HASH"
 	BREAK_OBJ        = "BREAK"
 	CONTINUE_OBJ     = "CONTINUE"
@@ -35,12 +41,25 @@ type Object interface {
 }
 
 type Builtin struct {
-	Fn BuiltinFunction
+	Name     string
+	Params   []string // Names of the parameters, for error messages
+	Variadic bool     // The last parameter takes any number of arguments
+	Fn       BuiltinFunction
 }
 
 func (b *Builtin) Type() ObjectType { return BUILTIN_OBJ }
 func (b *Builtin) Inspect() string  { return "builtin function" }
 
+// Signature describes how the builtin is called, as in push(array, value)
+func (b *Builtin) Signature() string {
+	params := make([]string, len(b.Params))
+	copy(params, b.Params)
+	if b.Variadic {
+		params[len(params)-1] = "..." + params[len(params)-1]
+	}
+	return b.Name + "(" + strings.Join(params, ", ") + ")"
+}
+
 type Integer struct {
 	Value int64
 }
@@ -48,6 +67,28 @@ type Integer struct {
 func (i *Integer) Type() ObjectType { return INTEGER_OBJ }
 func (i *Integer) Inspect() string  { return fmt.Sprintf("%d", i.Value) }
 
+// BigInteger is an integer too large for an int64
+type BigInteger struct {
+	Value *big.Int
+}
+
+func (bi *BigInteger) Type() ObjectType { return BIG_INTEGER_OBJ }
+func (bi *BigInteger) Inspect() string  { return bi.Value.String() }
+
+type Float struct {
+	Value float64
+}
+
+func (f *Float) Type() ObjectType { return FLOAT_OBJ }
+func (f *Float) Inspect() string {
+	s := strconv.FormatFloat(f.Value, 'g', -1, 64)
+	// Keep a decimal point so floats are never mistaken for integers
+	if !strings.ContainsAny(s, ".eEnN") {
+		s += ".0"
+	}
+	return s
+}
+
 type Boolean struct {
 	Value bool
 }
@@ -85,7 +126,10 @@ func (e *Error) Type() ObjectType { return ERROR_OBJ }
 func (e *Error) Inspect() string  { return "ERROR: " + e.Message }
 
 type Function struct {
+	Name       string // The name the function was defined with, empty if anonymous
 	Parameters []*ast.Identifier
+	Defaults   []ast.Expression // The default of each parameter, nil if it has none
+	Rest       *ast.Identifier  // Collects any extra arguments
 	Body       *ast.BlockStatement
 	Env        *Environment
 }
@@ -93,39 +137,45 @@ type Function struct {
 func (f *Function) Type() ObjectType { return FUNCTION_OBJ }
 func (f *Function) Inspect() string {
 	var out bytes.Buffer
-	params := []string{}
-	for _, p := range f.Parameters {
-		params = append(params, p.String())
-	}
 	out.WriteString("fn")
 	out.WriteString("(")
-	out.WriteString(strings.Join(params, ", "))
+	out.WriteString(f.parameterList())
 	out.WriteString(") {\n")
 	out.WriteString(f.Body.String())
 	out.WriteString("\n}")
 	return out.String()
 }
 
+// Signature describes how the function is called, as in add(a, b = 1)
+func (f *Function) Signature() string {
+	name := f.Name
+	if name == "" {
+		name = "fn"
+	}
+	return name + "(" + f.parameterList() + ")"
+}
+
+func (f *Function) parameterList() string {
+	params := []string{}
+	for i, p := range f.Parameters {
+		if i < len(f.Defaults) && f.Defaults[i] != nil {
+			params = append(params, p.String()+" = "+f.Defaults[i].String())
+		} else {
+			params = append(params, p.String())
+		}
+	}
+	if f.Rest != nil {
+		params = append(params, "..."+f.Rest.String())
+	}
+	return strings.Join(params, ", ")
+}
+
 type String struct {
 	Value string
 }
 
 func (s *String) Type() ObjectType { return STRING_OBJ }
-func (s *String) Inspect() string {
-	s.Value = strings.Replace(s.Value, `\n`, "\n", -1)
-	s.Value = strings.Replace(s.Value, `\t`, "\t", -1)
-	s.Value = strings.Replace(s.Value, `\r`, "\r", -1)
-	s.Value = strings.Replace(s.Value, `\"`, "\"", -1)
-	s.Value = strings.Replace(s.Value, `\'`, "'", -1)
-	s.Value = strings.Replace(s.Value, `\\`, "\\", -1)
-	s.Value = strings.Replace(s.Value, `\0`, "\x00", -1)
-	s.Value = strings.Replace(s.Value, `\a`, "\a", -1)
-	s.Value = strings.Replace(s.Value, `\b`, "\b", -1)
-	s.Value = strings.Replace(s.Value, `\f`, "\f", -1)
-	s.Value = strings.Replace(s.Value, `\v`, "\v", -1)
-	s.Value = strings.Replace(s.Value, `\?`, "?", -1)
-	return s.Value
-}
+func (s *String) Inspect() string  { return s.Value }
 
 type Array struct {
 	Elements []Object
@@ -144,6 +194,98 @@ func (ao *Array) Inspect() string {
 	return out.String()
 }
 
+// Range is the sequence of integers from Start towards End in increments of
+// Step, such as 1..10 or 0..<n step 2. Its values are computed on demand
+// instead of being stored.
+type Range struct {
+	Start     int64
+	End       int64
+	Step      int64 // Never zero, negative for descending ranges
+	Inclusive bool  // Whether End itself is part of the range (.. rather than ..<)
+}
+
+func (r *Range) Type() ObjectType { return RANGE_OBJ }
+func (r *Range) Inspect() string {
+	var out bytes.Buffer
+	out.WriteString(strconv.FormatInt(r.Start, 10))
+	if r.Inclusive {
+		out.WriteString("..")
+	} else {
+		out.WriteString("..<")
+	}
+	out.WriteString(strconv.FormatInt(r.End, 10))
+	if r.Step != 1 {
+		out.WriteString(" step " + strconv.FormatInt(r.Step, 10))
+	}
+	return out.String()
+}
+
+// Len returns the number of values in the range. Ranges with more values than
+// fit in an int64 are rejected when they are built, see TooLong.
+func (r *Range) Len() int64 {
+	n, _ := r.length()
+	return int64(n)
+}
+
+// TooLong reports whether the range has more values than fit in an int64
+func (r *Range) TooLong() bool {
+	n, ok := r.length()
+	return !ok || n > math.MaxInt64
+}
+
+// length counts the values of the range, with ok false when the count does
+// not fit in a uint64
+func (r *Range) length() (n uint64, ok bool) {
+	distance, ahead := r.offset(r.End)
+	step := r.absStep()
+	switch {
+	case !ahead, !r.Inclusive && distance == 0:
+		return 0, true
+	case r.Inclusive:
+		n = distance/step + 1
+		return n, n != 0
+	default:
+		return (distance-1)/step + 1, true
+	}
+}
+
+// offset returns how far n lies from Start in the direction of the step, or
+// false when n lies behind Start. It is worked out in uint64, where the
+// distance between any two int64 values fits.
+func (r *Range) offset(n int64) (uint64, bool) {
+	if r.Step > 0 {
+		if n < r.Start {
+			return 0, false
+		}
+		return uint64(n) - uint64(r.Start), true
+	}
+	if n > r.Start {
+		return 0, false
+	}
+	return uint64(r.Start) - uint64(n), true
+}
+
+func (r *Range) absStep() uint64 {
+	if r.Step < 0 {
+		return uint64(-(r.Step + 1)) + 1
+	}
+	return uint64(r.Step)
+}
+
+// At returns the i-th value of the range, for 0 <= i < Len(). The arithmetic
+// wraps around, which gives the right value because the result lies between
+// the bounds.
+func (r *Range) At(i int64) int64 {
+	return int64(uint64(r.Start) + uint64(i)*uint64(r.Step))
+}
+
+// Contains reports whether n is one of the values of the range
+func (r *Range) Contains(n int64) bool {
+	offset, ahead := r.offset(n)
+	step := r.absStep()
+	return ahead && offset%step == 0 && offset/step < uint64(r.Len())
+}
+
 type HashKey struct {
 	Type  ObjectType
 	Value uint64
@@ -161,6 +303,24 @@ func (b *Boolean) HashKey() HashKey {
 func (i *Integer) HashKey() HashKey {
 	return HashKey{Type: i.Type(), Value: uint64(i.Value)}
 }
+func (bi *BigInteger) HashKey() HashKey {
+	h := fnv.New64a()
+	h.Write([]byte(bi.Value.String()))
+	return HashKey{Type: bi.Type(), Value: h.Sum64()}
+}
+
+// HashKey of a whole float is the key of the equal integer, so that numbers
+// that are == are also the same hash key, as in {1: "a"}[1.0]
+func (f *Float) HashKey() HashKey {
+	if f.Value == math.Trunc(f.Value) && !math.IsInf(f.Value, 0) {
+		if f.Value >= math.MinInt64 && f.Value < math.MaxInt64 {
+			return (&Integer{Value: int64(f.Value)}).HashKey()
+		}
+		value, _ := big.NewFloat(f.Value).Int(nil)
+		return (&BigInteger{Value: value}).HashKey()
+	}
+	return HashKey{Type: f.Type(), Value: math.Float64bits(f.Value)}
+}
 func (s *String) HashKey() HashKey {
 	h := fnv.New64a()
 	h.Write([]byte(s.Value))
@@ -1,10 +1,15 @@
 package parser
 
 import (
+	"errors"
 	"fmt"
+	"math/big"
+	"sort"
 	"strconv"
+	"strings"
 
 	"github.com/anilang-official/AniLang/ast"
+	"github.com/anilang-official/AniLang/diagnostic"
 	"github.com/anilang-official/AniLang/lexer"
 	"github.com/anilang-official/AniLang/token"
 )
@@ -12,26 +17,59 @@ import (
 const (
 	_ int = iota
 	Lowest
-	Equals        // =
-	LessOrGreater // < or >
+	Assign        // = += -= *= /= %= &= |= ^=
+	LogicalOr     // ||
+	LogicalAnd    // &&
+	BitwiseOr     // |
+	BitwiseXor    // ^
+	BitwiseAnd    // &
+	Equals        // ==
+	LessOrGreater // < > <= >= in
+	Range         // .. or ..<
+	Shift         // << or >>
 	Sum           // +
-	Product       // *
-	Prefix        // -X or !X
+	Product       // * / ~/ %
+	Prefix        // -X or !X or ~X
+	Power         // ** (binds tighter than prefix operators, so -2 ** 2 is -4)
 	Call          // myFunction(X)
 	Index         // array[index]
 )
 
 var precedences = map[token.TokenType]int{
-	token.EQUAL:       Equals,
-	token.NOTEQUAL:    Equals,
-	token.LESSTHAN:    LessOrGreater,
-	token.GREATERTHAN: LessOrGreater,
-	token.PLUS:        Sum,
-	token.MINUS:       Sum,
-	token.SLASH:       Product,
-	token.ASTERISK:    Product,
-	token.LPAREN:      Call,
-	token.LBRACKET:    Index,
+	token.ASSIGN:             Assign,
+	token.PLUSEQUAL:          Assign,
+	token.MINUSEQUAL:         Assign,
+	token.MULTIPLYEQUAL:      Assign,
+	token.DIVIDEEQUAL:        Assign,
+	token.MODULOEQUAL:        Assign,
+	token.BITWISEANDEQUAL:    Assign,
+	token.BITWISEOREQUAL:     Assign,
+	token.BITWISEXOREQUAL:    Assign,
+	token.OR:                 LogicalOr,
+	token.AND:                LogicalAnd,
+	token.BITWISEOR:          BitwiseOr,
+	token.BITWISEXOR:         BitwiseXor,
+	token.BITWISEAND:         BitwiseAnd,
+	token.EQUAL:              Equals,
+	token.NOTEQUAL:           Equals,
+	token.LESSTHAN:           LessOrGreater,
+	token.GREATERTHAN:        LessOrGreater,
+	token.LESSTHANOREQUAL:    LessOrGreater,
+	token.GREATERTHANOREQUAL: LessOrGreater,
+	token.IN:                 LessOrGreater,
+	token.DOTDOT:             Range,
+	token.DOTDOTLESS:         Range,
+	token.LEFTSHIFT:          Shift,
+	token.RIGHTSHIFT:         Shift,
+	token.PLUS:               Sum,
+	token.MINUS:              Sum,
+	token.SLASH:              Product,
+	token.INTDIVIDE:          Product,
+	token.ASTERISK:           Product,
+	token.MODULO:             Product,
+	token.POW:                Power,
+	token.LPAREN:             Call,
+	token.LBRACKET:           Index,
 }
 
 type (
@@ -40,45 +78,80 @@ type (
 )
 
 type Parser struct {
-	l      *lexer.Lexer
-	errors []string
+	l           *lexer.Lexer
+	diagnostics []*diagnostic.Diagnostic
+	panicking   bool // Set after an error until the parser resynchronizes
 
 	currentToken token.Token
 	peekToken    token.Token
+	lookahead    []token.Token // Tokens after peekToken that were read by peekN
+
+	// guardArrow is the position of the => that ends the match guard being
+	// parsed, which must not be taken for the => of an arrow function
+	guardArrow token.Position
 
 	prefixParseFns map[token.TokenType]prefixParseFn
 	infixParseFns  map[token.TokenType]infixParseFn
 }
 
 func New(l *lexer.Lexer) *Parser {
-	p := &Parser{l: l, errors: []string{}}
+	p := &Parser{l: l, diagnostics: []*diagnostic.Diagnostic{}}
 
 	p.prefixParseFns = make(map[token.TokenType]prefixParseFn)
+	p.registerPrefix(token.ILLEGAL, p.parseIllegal)
 	p.registerPrefix(token.IDENTFIER, p.parseIdentifier)
 	p.registerPrefix(token.INT, p.parseIntegerLiteral)
+	p.registerPrefix(token.FLOAT, p.parseFloatLiteral)
 	p.registerPrefix(token.BANG, p.parsePrefixExpression)
 	p.registerPrefix(token.MINUS, p.parsePrefixExpression)
+	p.registerPrefix(token.BITWISENOT, p.parsePrefixExpression)
 	p.registerPrefix(token.TRUE, p.parseBoolean)
 	p.registerPrefix(token.FALSE, p.parseBoolean)
 	p.registerPrefix(token.LPAREN, p.parseGroupedExpression)
 	p.registerPrefix(token.IF, p.parseIfExpression)
 	p.registerPrefix(token.FUNCTION, p.parseFunctionLiteral)
 	p.registerPrefix(token.STRING, p.parseStringLiteral)
+	p.registerPrefix(token.STRINGHEAD, p.parseInterpolatedString)
 	p.registerPrefix(token.LBRACKET, p.parseArrayLiteral)
 	p.registerPrefix(token.LBRACE, p.parseHashLiteral)
 	p.registerPrefix(token.FOR, p.parseForExpression)
 	p.registerPrefix(token.WHILE, p.parseWhileExpression)
+	p.registerPrefix(token.MATCH, p.parseMatchExpression)
 	p.registerPrefix(token.NULL, p.parseNullLiteral)
 
 	p.infixParseFns = make(map[token.TokenType]infixParseFn)
 	p.registerInfix(token.PLUS, p.parseInfixExpression)
 	p.registerInfix(token.MINUS, p.parseInfixExpression)
 	p.registerInfix(token.SLASH, p.parseInfixExpression)
+	p.registerInfix(token.INTDIVIDE, p.parseInfixExpression)
 	p.registerInfix(token.ASTERISK, p.parseInfixExpression)
+	p.registerInfix(token.MODULO, p.parseInfixExpression)
+	p.registerInfix(token.POW, p.parseInfixExpression)
+	p.registerInfix(token.BITWISEAND, p.parseInfixExpression)
+	p.registerInfix(token.BITWISEOR, p.parseInfixExpression)
+	p.registerInfix(token.BITWISEXOR, p.parseInfixExpression)
+	p.registerInfix(token.LEFTSHIFT, p.parseInfixExpression)
+	p.registerInfix(token.RIGHTSHIFT, p.parseInfixExpression)
 	p.registerInfix(token.EQUAL, p.parseInfixExpression)
 	p.registerInfix(token.NOTEQUAL, p.parseInfixExpression)
 	p.registerInfix(token.LESSTHAN, p.parseInfixExpression)
 	p.registerInfix(token.GREATERTHAN, p.parseInfixExpression)
+	p.registerInfix(token.LESSTHANOREQUAL, p.parseInfixExpression)
+	p.registerInfix(token.GREATERTHANOREQUAL, p.parseInfixExpression)
+	p.registerInfix(token.ASSIGN, p.parseAssignExpression)
+	p.registerInfix(token.PLUSEQUAL, p.parseAssignExpression)
+	p.registerInfix(token.MINUSEQUAL, p.parseAssignExpression)
+	p.registerInfix(token.MULTIPLYEQUAL, p.parseAssignExpression)
+	p.registerInfix(token.DIVIDEEQUAL, p.parseAssignExpression)
+	p.registerInfix(token.MODULOEQUAL, p.parseAssignExpression)
+	p.registerInfix(token.BITWISEANDEQUAL, p.parseAssignExpression)
+	p.registerInfix(token.BITWISEOREQUAL, p.parseAssignExpression)
+	p.registerInfix(token.BITWISEXOREQUAL, p.parseAssignExpression)
+	p.registerInfix(token.AND, p.parseLogicalExpression)
+	p.registerInfix(token.OR, p.parseLogicalExpression)
+	p.registerInfix(token.IN, p.parseInfixExpression)
+	p.registerInfix(token.DOTDOT, p.parseRangeExpression)
+	p.registerInfix(token.DOTDOTLESS, p.parseRangeExpression)
 	p.registerInfix(token.LPAREN, p.parseCallExpression)
 	p.registerInfix(token.LBRACKET, p.parseIndexExpression)
 
@@ -89,23 +162,52 @@ func New(l *lexer.Lexer) *Parser {
 	return p
 }
 
+// ParseProgram parses the whole input. When a statement has a syntax error it
+// is left out of the returned program and parsing resumes at the next
+// statement, so every independent error is reported in one pass.
 func (p *Parser) ParseProgram() *ast.Program {
 	program := &ast.Program{}
 	program.Statements = []ast.Statement{}
+	start := p.currentToken.Pos
 
 	for p.currentToken.Type != token.EOF {
 		stmt := p.parseStatement()
-		if stmt != nil {
+		if p.panicking {
+			p.synchronize()
+			// A '}' cannot start a statement at the top level, it closes a
+			// block that failed to parse
+			for p.peekTokenIs(token.RBRACE) {
+				p.nextToken()
+			}
+		} else if stmt != nil {
 			program.Statements = append(program.Statements, stmt)
 		}
 		p.nextToken()
 	}
 
+	p.finishNode(program, start)
 	return program
 }
 
+// Errors returns the messages of every diagnostic reported while parsing
 func (p *Parser) Errors() []string {
-	return p.errors
+	diagnostics := p.Diagnostics()
+	messages := make([]string, len(diagnostics))
+	for i, d := range diagnostics {
+		messages[i] = d.Message
+	}
+	return messages
+}
+
+// Diagnostics returns every problem found by the lexer and the parser, with
+// source spans, in source order
+func (p *Parser) Diagnostics() []*diagnostic.Diagnostic {
+	diagnostics := append([]*diagnostic.Diagnostic{}, p.l.Errors()...)
+	diagnostics = append(diagnostics, p.diagnostics...)
+	sort.SliceStable(diagnostics, func(i, j int) bool {
+		return diagnostics[i].Span.Start.Offset < diagnostics[j].Span.Start.Offset
+	})
+	return diagnostics
 }
 
 // Statements
@@ -113,7 +215,10 @@ func (p *Parser) Errors() []string {
 func (p *Parser) parseStatement() ast.Statement {
 	switch p.currentToken.Type {
 	case token.LET:
-		return p.parseLetStatement()
+		if stmt := p.parseLetStatement(); stmt != nil {
+			return stmt
+		}
+		return nil
 	case token.RETURN:
 		return p.parseReturnStatement()
 	case token.BREAK:
@@ -128,11 +233,15 @@ func (p *Parser) parseStatement() ast.Statement {
 func (p *Parser) parseLetStatement() *ast.LetStatement {
 	stmt := &ast.LetStatement{Token: p.currentToken}
 
+	if p.peekTokenIs(token.LBRACKET) || p.peekTokenIs(token.LBRACE) {
+		return p.parseDestructuringLet(stmt)
+	}
+
 	if !p.expectPeek(token.IDENTFIER) {
 		return nil
 	}
 
-	stmt.Name = &ast.Identifier{Token: p.currentToken, Value: p.currentToken.Literal}
+	stmt.Name = p.newIdentifier()
 
 	if p.expectPeekNoError(token.ASSIGN) {
 		stmt.Assignment = token.Token{Type: token.ASSIGN, Literal: "="}
@@ -157,6 +266,8 @@ func (p *Parser) parseLetStatement() *ast.LetStatement {
 	} else if p.expectPeekNoError(token.DECREMENT) {
 		stmt.Assignment = token.Token{Type: token.DECREMENT, Literal: "--"}
 	} else {
+		p.errorAt(p.peekToken, "expected assignment operator after %s, got %s instead",
+			stmt.Name.Value, p.peekToken.Type)
 		return nil
 	}
 
@@ -168,9 +279,40 @@ func (p *Parser) parseLetStatement() *ast.LetStatement {
 		stmt.Value = p.parseExpression(Lowest)
 	}
 
+	if function, ok := stmt.Value.(*ast.FunctionLiteral); ok && stmt.Assignment.Type == token.ASSIGN {
+		function.Name = stmt.Name.Value
+	}
+
 	if p.peekTokenIs(token.SEMICOLON) {
 		p.nextToken()
 	}
+
+	p.finishNode(stmt, stmt.Token.Pos)
+	return stmt
+}
+
+// parseDestructuringLet parses let [a, b] = value; or let {a, b} = value;
+// which only support plain assignment
+func (p *Parser) parseDestructuringLet(stmt *ast.LetStatement) *ast.LetStatement {
+	p.nextToken()
+	stmt.Pattern = p.parseDestructuringPattern()
+	if stmt.Pattern == nil {
+		return nil
+	}
+
+	if !p.expectPeek(token.ASSIGN) {
+		return nil
+	}
+	stmt.Assignment = p.currentToken
+
+	p.nextToken()
+	stmt.Value = p.parseExpression(Lowest)
+
+	if p.peekTokenIs(token.SEMICOLON) {
+		p.nextToken()
+	}
+
+	p.finishNode(stmt, stmt.Token.Pos)
 	return stmt
 }
 
@@ -185,6 +327,7 @@ func (p *Parser) parseReturnStatement() *ast.ReturnStatement {
 		p.nextToken()
 	}
 
+	p.finishNode(stmt, stmt.Token.Pos)
 	return stmt
 }
 
@@ -195,6 +338,7 @@ func (p *Parser) parseBreakStatement() *ast.BreakStatement {
 		p.nextToken()
 	}
 
+	p.finishNode(stmt, stmt.Token.Pos)
 	return stmt
 }
 
@@ -205,6 +349,7 @@ func (p *Parser) parseContinueStatement() *ast.ContinueStatement {
 		p.nextToken()
 	}
 
+	p.finishNode(stmt, stmt.Token.Pos)
 	return stmt
 }
 
@@ -217,6 +362,7 @@ func (p *Parser) parseExpressionStatement() *ast.ExpressionStatement {
 		p.nextToken()
 	}
 
+	p.finishNode(stmt, stmt.Token.Pos)
 	return stmt
 }
 
@@ -229,7 +375,11 @@ func (p *Parser) parseExpression(precedence int) ast.Expression {
 		return nil
 	}
 
+	start := p.currentToken.Pos
 	leftExp := prefix()
+	if leftExp != nil {
+		p.finishNode(leftExp, start)
+	}
 
 	for !p.peekTokenIs(token.SEMICOLON) && precedence < p.peekPrecedence() {
 		infix := p.infixParseFns[p.peekToken.Type]
@@ -239,6 +389,9 @@ func (p *Parser) parseExpression(precedence int) ast.Expression {
 
 		p.nextToken()
 		leftExp = infix(leftExp)
+		if leftExp != nil {
+			p.finishNode(leftExp, start)
+		}
 	}
 
 	return leftExp
@@ -247,16 +400,61 @@ func (p *Parser) parseExpression(precedence int) ast.Expression {
 // Prefix expressions
 
 func (p *Parser) parseIdentifier() ast.Expression {
-	return &ast.Identifier{Token: p.currentToken, Value: p.currentToken.Literal}
+	if p.isArrow(p.peekToken) {
+		return p.parseArrowFunction()
+	}
+	return p.newIdentifier()
+}
+
+// newIdentifier builds an identifier node from the current token
+func (p *Parser) newIdentifier() *ast.Identifier {
+	identifier := &ast.Identifier{Token: p.currentToken, Value: p.currentToken.Literal}
+	p.finishNode(identifier, p.currentToken.Pos)
+	return identifier
 }
 
 func (p *Parser) parseIntegerLiteral() ast.Expression {
 	lit := &ast.IntegerLiteral{Token: p.currentToken}
 
-	value, err := strconv.ParseInt(p.currentToken.Literal, 0, 64)
+	digits, base := integerDigits(p.currentToken.Literal)
+	value, err := strconv.ParseInt(digits, base, 64)
+	if errors.Is(err, strconv.ErrRange) {
+		if value, ok := new(big.Int).SetString(digits, base); ok {
+			return &ast.BigIntegerLiteral{Token: p.currentToken, Value: value}
+		}
+	}
 	if err != nil {
-		msg := fmt.Sprintf("could not parse %q as integer", p.currentToken.Literal)
-		p.errors = append(p.errors, msg)
+		p.errorAt(p.currentToken, "could not parse %q as integer", p.currentToken.Literal)
+		return nil
+	}
+
+	lit.Value = value
+	return lit
+}
+
+// integerDigits strips the base prefix and digit separators from an integer
+// literal, returning the remaining digits and their base
+func integerDigits(literal string) (string, int) {
+	literal = strings.ReplaceAll(literal, "_", "")
+	if len(literal) > 2 && literal[0] == '0' {
+		switch literal[1] {
+		case 'x', 'X':
+			return literal[2:], 16
+		case 'o', 'O':
+			return literal[2:], 8
+		case 'b', 'B':
+			return literal[2:], 2
+		}
+	}
+	return literal, 10
+}
+
+func (p *Parser) parseFloatLiteral() ast.Expression {
+	lit := &ast.FloatLiteral{Token: p.currentToken}
+
+	value, err := strconv.ParseFloat(strings.ReplaceAll(p.currentToken.Literal, "_", ""), 64)
+	if err != nil {
+		p.errorAt(p.currentToken, "could not parse %q as float", p.currentToken.Literal)
 		return nil
 	}
 
@@ -277,6 +475,13 @@ func (p *Parser) parsePrefixExpression() ast.Expression {
 	return expression
 }
 
+// parseIllegal reports a token the lexer could not make sense of. Malformed
+// literals have already been reported by the lexer, so they only need recovery.
+func (p *Parser) parseIllegal() ast.Expression {
+	p.illegalTokenError(p.currentToken)
+	return nil
+}
+
 func (p *Parser) parseBoolean() ast.Expression {
 	return &ast.Boolean{Token: p.currentToken, Value: p.currentTokenIs(token.TRUE)}
 }
@@ -286,6 +491,10 @@ func (p *Parser) parseNullLiteral() ast.Expression {
 }
 
 func (p *Parser) parseGroupedExpression() ast.Expression {
+	if p.isArrowParameterList() {
+		return p.parseArrowFunction()
+	}
+
 	// get expression
 	p.nextToken()
 	exp := p.parseExpression(Lowest)
@@ -297,6 +506,66 @@ func (p *Parser) parseGroupedExpression() ast.Expression {
 	return exp
 }
 
+// isArrowParameterList reports whether the ( at the current token opens the
+// parameters of an arrow function rather than a grouped expression, by
+// looking past the matching ) for a =>
+func (p *Parser) isArrowParameterList() bool {
+	depth := 1
+	for n := 1; ; n++ {
+		switch tok := p.peekN(n); tok.Type {
+		case token.LPAREN:
+			depth++
+		case token.RPAREN:
+			depth--
+			if depth == 0 {
+				return p.isArrow(p.peekN(n + 1))
+			}
+		case token.EOF:
+			return false
+		}
+	}
+}
+
+func (p *Parser) isArrow(tok token.Token) bool {
+	return tok.Type == token.ARROW && tok.Pos != p.guardArrow
+}
+
+// parseArrowFunction parses the shorthands x => x * 2 and (x, y) => { ... }
+// into a function literal. A body without braces is a single expression,
+// which the function returns.
+func (p *Parser) parseArrowFunction() ast.Expression {
+	fn := token.Token{
+		Type:    token.FUNCTION,
+		Literal: token.LookupTokenIdentifier(token.FUNCTION),
+		Pos:     p.currentToken.Pos,
+		End:     p.currentToken.End,
+	}
+	lit := &ast.FunctionLiteral{Token: fn}
+
+	if p.currentTokenIs(token.IDENTFIER) {
+		lit.Parameters = []*ast.Identifier{p.newIdentifier()}
+		lit.Defaults = []ast.Expression{nil}
+	} else {
+		p.parseFunctionParameters(lit)
+	}
+
+	if !p.expectPeek(token.ARROW) {
+		return nil
+	}
+
+	p.nextToken()
+	if p.currentTokenIs(token.LBRACE) {
+		lit.Body = p.parseBlockStatement()
+	} else {
+		lit.Body = p.parseExpressionBody()
+		if lit.Body == nil {
+			return nil
+		}
+	}
+
+	return lit
+}
+
 func (p *Parser) parseIfExpression() ast.Expression {
 	expression := &ast.IfExpression{Token: p.currentToken}
 
@@ -359,6 +628,7 @@ func (p *Parser) parseElseIfStatement() *ast.ElseIfExpression {
 
 	stmt.Consequence = p.parseBlockStatement()
 
+	p.finishNode(stmt, stmt.Token.Pos)
 	return stmt
 }
 
@@ -370,12 +640,18 @@ func (p *Parser) parseBlockStatement() *ast.BlockStatement {
 
 	for !p.currentTokenIs(token.RBRACE) && !p.currentTokenIs(token.EOF) {
 		stmt := p.parseStatement()
-		if stmt != nil {
+		if p.panicking {
+			p.synchronize()
+			if p.currentTokenIs(token.RBRACE) {
+				break // The error was at the end of the block
+			}
+		} else if stmt != nil {
 			block.Statements = append(block.Statements, stmt)
 		}
 		p.nextToken()
 	}
 
+	p.finishNode(block, block.Token.Pos)
 	return block
 }
 
@@ -386,7 +662,7 @@ func (p *Parser) parseFunctionLiteral() ast.Expression {
 		return nil
 	}
 
-	lit.Parameters = p.parseFunctionParameters()
+	p.parseFunctionParameters(lit)
 
 	if !p.expectPeek(token.LBRACE) {
 		return nil
@@ -404,16 +680,25 @@ func (p *Parser) parseForExpression() ast.Expression {
 		return nil
 	}
 
-	// get condition
 	p.nextToken()
-	if !p.currentTokenIs(token.LET) {
-		p.peekError(token.LET)
-		return nil
+	if p.currentTokenIs(token.IDENTFIER) && (p.peekTokenIs(token.IN) || p.peekTokenIs(token.COMMA)) {
+		return p.parseForInExpression(expression.Token)
 	}
-	expression.Initialization = p.parseLetStatement()
-	if expression.Initialization.Assignment.Literal != token.ASSIGN {
-		p.peekError(token.ASSIGN)
-		return nil
+
+	// get initialization
+	if p.currentTokenIs(token.LET) {
+		initialization := p.parseLetStatement()
+		if initialization == nil {
+			return nil
+		}
+		if initialization.Assignment.Literal != token.ASSIGN {
+			p.errorAt(initialization.Assignment, "expected %s in for loop initialization, got %s instead",
+				token.ASSIGN, initialization.Assignment.Literal)
+			return nil
+		}
+		expression.Initialization = initialization
+	} else {
+		expression.Initialization = p.parseExpressionStatement()
 	}
 
 	// get condition
@@ -424,20 +709,57 @@ func (p *Parser) parseForExpression() ast.Expression {
 		return nil
 	}
 
-	// get condition
+	// get increment or decrement
 	p.nextToken()
-	if !p.currentTokenIs(token.LET) {
-		p.peekError(token.LET)
+	if p.currentTokenIs(token.LET) {
+		step := p.parseLetStatement()
+		if step == nil {
+			return nil
+		}
+		if step.Assignment.Literal == token.ASSIGN {
+			p.errorAt(step.Assignment, "not expecting next token to be %s", token.ASSIGN)
+			return nil
+		}
+		expression.IncrementOrDecrement = step
+	} else {
+		expression.IncrementOrDecrement = p.parseExpressionStatement()
+	}
+
+	if !p.expectPeek(token.RPAREN) {
 		return nil
 	}
 
-	expression.IncrementOrDecrement = p.parseLetStatement()
-	if expression.IncrementOrDecrement.Assignment.Literal == token.ASSIGN {
-		msg := fmt.Sprintf("not expecting next token to be %s", token.ASSIGN)
-		p.errors = append(p.errors, msg)
+	if !p.expectPeek(token.LBRACE) {
 		return nil
 	}
 
+	expression.Consequence = p.parseBlockStatement()
+
+	return expression
+}
+
+// parseForInExpression parses the rest of a for (x in collection) or
+// for (k, v in collection) loop, starting at the first variable
+func (p *Parser) parseForInExpression(forToken token.Token) ast.Expression {
+	expression := &ast.ForInExpression{Token: forToken}
+
+	expression.Value = p.newIdentifier()
+	if p.peekTokenIs(token.COMMA) {
+		p.nextToken()
+		if !p.expectPeek(token.IDENTFIER) {
+			return nil
+		}
+		expression.Key = expression.Value
+		expression.Value = p.newIdentifier()
+	}
+
+	if !p.expectPeek(token.IN) {
+		return nil
+	}
+
+	p.nextToken()
+	expression.Iterable = p.parseExpression(Lowest)
+
 	if !p.expectPeek(token.RPAREN) {
 		return nil
 	}
@@ -451,16 +773,15 @@ func (p *Parser) parseForExpression() ast.Expression {
 	return expression
 }
 
-func (p *Parser) parseWhileExpression() ast.Expression {
-	expression := &ast.WhileExpression{Token: p.currentToken}
+func (p *Parser) parseMatchExpression() ast.Expression {
+	expression := &ast.MatchExpression{Token: p.currentToken}
 
 	if !p.expectPeek(token.LPAREN) {
 		return nil
 	}
 
-	// get condition
 	p.nextToken()
-	expression.Condition = p.parseExpression(Lowest)
+	expression.Subject = p.parseExpression(Lowest)
 
 	if !p.expectPeek(token.RPAREN) {
 		return nil
@@ -470,43 +791,397 @@ func (p *Parser) parseWhileExpression() ast.Expression {
 		return nil
 	}
 
-	expression.Consequence = p.parseBlockStatement()
+	for !p.peekTokenIs(token.RBRACE) {
+		p.nextToken()
+		arm := p.parseMatchArm()
+		if arm == nil {
+			return nil
+		}
+		expression.Arms = append(expression.Arms, arm)
+
+		// Arms are separated by commas, which are optional after a block body
+		if p.peekTokenIs(token.COMMA) {
+			p.nextToken()
+		} else if !p.peekTokenIs(token.RBRACE) && arm.Body.Token.Type != token.LBRACE {
+			p.peekError(token.COMMA)
+			return nil
+		}
+	}
+	p.nextToken() // The closing '}'
 
 	return expression
 }
 
-func (p *Parser) parseFunctionParameters() []*ast.Identifier {
-	var identifiers []*ast.Identifier
+// parseMatchArm parses a pattern, an optional if guard, and the => body. The
+// body is either a block or a single expression.
+func (p *Parser) parseMatchArm() *ast.MatchArm {
+	arm := &ast.MatchArm{}
+	start := p.currentToken.Pos
 
-	// TODO Support identifier
-	if p.peekTokenIs(token.RPAREN) {
+	arm.Pattern = p.parsePattern()
+	if arm.Pattern == nil {
+		return nil
+	}
+
+	if p.peekTokenIs(token.IF) {
+		p.nextToken()
+		outer := p.guardArrow
+		p.guardArrow = p.findGuardArrow()
 		p.nextToken()
-		return identifiers
+		arm.Guard = p.parseExpression(Lowest)
+		p.guardArrow = outer
 	}
 
+	if !p.expectPeek(token.ARROW) {
+		return nil
+	}
+	arm.Token = p.currentToken
+
 	p.nextToken()
+	if p.currentTokenIs(token.LBRACE) {
+		arm.Body = p.parseBlockStatement()
+	} else {
+		arm.Body = p.parseExpressionBody()
+		if arm.Body == nil {
+			return nil
+		}
+	}
 
-	identifier := &ast.Identifier{Token: p.currentToken, Value: p.currentToken.Literal}
-	identifiers = append(identifiers, identifier)
+	p.finishNode(arm, start)
+	return arm
+}
 
-	for p.peekTokenIs(token.COMMA) {
+// findGuardArrow returns the position of the => that ends the match guard
+// after the current token, which is the first one outside any brackets
+func (p *Parser) findGuardArrow() token.Position {
+	depth := 0
+	for n := 1; ; n++ {
+		switch tok := p.peekN(n); tok.Type {
+		case token.LPAREN, token.LBRACKET, token.LBRACE:
+			depth++
+		case token.RPAREN, token.RBRACKET, token.RBRACE:
+			depth--
+		case token.ARROW:
+			if depth == 0 {
+				return tok.Pos
+			}
+		case token.EOF:
+			return token.Position{}
+		}
+	}
+}
+
+// parseExpressionBody parses a single expression as a block, for bodies that
+// are written without braces
+func (p *Parser) parseExpressionBody() *ast.BlockStatement {
+	stmt := &ast.ExpressionStatement{Token: p.currentToken}
+	stmt.Expression = p.parseExpression(Lowest)
+	if stmt.Expression == nil {
+		return nil
+	}
+	p.finishNode(stmt, stmt.Token.Pos)
+
+	block := &ast.BlockStatement{Token: stmt.Token, Statements: []ast.Statement{stmt}}
+	p.finishNode(block, stmt.Token.Pos)
+	return block
+}
+
+// Patterns
+
+// parsePattern parses a pattern, including alternatives separated by |
+func (p *Parser) parsePattern() ast.Pattern {
+	start := p.currentToken.Pos
+
+	pattern := p.parsePrimaryPattern()
+	if pattern == nil || !p.peekTokenIs(token.BITWISEOR) {
+		return pattern
+	}
+
+	alternative := &ast.AlternativePattern{Alternatives: []ast.Pattern{pattern}}
+	for p.peekTokenIs(token.BITWISEOR) {
+		p.nextToken()
+		p.nextToken()
+		pattern = p.parsePrimaryPattern()
+		if pattern == nil {
+			return nil
+		}
+		alternative.Alternatives = append(alternative.Alternatives, pattern)
+	}
+
+	p.finishNode(alternative, start)
+	return alternative
+}
+
+func (p *Parser) parsePrimaryPattern() ast.Pattern {
+	var pattern ast.Pattern
+	start := p.currentToken
+
+	switch p.currentToken.Type {
+	case token.IDENTFIER:
+		if p.currentToken.Literal == "_" {
+			pattern = &ast.WildcardPattern{Token: p.currentToken}
+		} else {
+			pattern = &ast.BindingPattern{Name: p.newIdentifier()}
+		}
+	case token.MINUS, token.INT, token.FLOAT, token.STRING, token.TRUE, token.FALSE, token.NULL:
+		if p.currentTokenIs(token.MINUS) && !p.peekTokenIs(token.INT) && !p.peekTokenIs(token.FLOAT) {
+			p.errorAt(p.peekToken, "expected a number after - in pattern, got %s instead", p.peekToken.Type)
+			return nil
+		}
+		value := p.parseExpression(Prefix)
+		if value == nil {
+			return nil
+		}
+		pattern = &ast.LiteralPattern{Token: start, Value: value}
+	case token.LBRACKET:
+		return p.parseArrayPattern(p.parsePattern)
+	case token.LBRACE:
+		return p.parseHashPattern(p.parsePattern)
+	default:
+		p.errorAt(p.currentToken, "expected a pattern, got %s instead", p.currentToken.Type)
+		return nil
+	}
+
+	p.finishNode(pattern, start.Pos)
+	return pattern
+}
+
+// parseDestructuringPattern parses the target of a destructuring let. Unlike
+// match patterns these cannot fail to match on their value, so only names, _,
+// and array and hash patterns are allowed, and elements may have defaults.
+func (p *Parser) parseDestructuringPattern() ast.Pattern {
+	switch p.currentToken.Type {
+	case token.IDENTFIER:
+		if p.currentToken.Literal == "_" {
+			pattern := &ast.WildcardPattern{Token: p.currentToken}
+			p.finishNode(pattern, pattern.Token.Pos)
+			return pattern
+		}
+		pattern := &ast.BindingPattern{Name: p.newIdentifier()}
+		p.finishNode(pattern, pattern.Name.Token.Pos)
+		return pattern
+	case token.LBRACKET:
+		return p.parseArrayPattern(p.parseDestructuringElement)
+	case token.LBRACE:
+		return p.parseHashPattern(p.parseDestructuringElement)
+	default:
+		p.errorAt(p.currentToken, "expected a name, array pattern or hash pattern, got %s instead",
+			p.currentToken.Type)
+		return nil
+	}
+}
+
+// parseDestructuringElement parses an element of a destructuring pattern,
+// with an optional default as in [a, b = 10]
+func (p *Parser) parseDestructuringElement() ast.Pattern {
+	start := p.currentToken.Pos
+
+	pattern := p.parseDestructuringPattern()
+	if pattern == nil || !p.peekTokenIs(token.ASSIGN) {
+		return pattern
+	}
+	p.nextToken()
+	p.nextToken()
+
+	withDefault := &ast.DefaultPattern{Pattern: pattern, Default: p.parseExpression(Lowest)}
+	if withDefault.Default == nil {
+		return nil
+	}
+
+	p.finishNode(withDefault, start)
+	return withDefault
+}
+
+// parseArrayPattern parses [a, b] or [first, ..rest], where the rest element
+// must come last. Elements are parsed with element, so the same syntax serves
+// match arms and destructuring.
+func (p *Parser) parseArrayPattern(element func() ast.Pattern) ast.Pattern {
+	pattern := &ast.ArrayPattern{Token: p.currentToken}
+
+	for !p.peekTokenIs(token.RBRACKET) {
 		p.nextToken()
+
+		if p.currentTokenIs(token.DOTDOT) {
+			pattern.HasRest = true
+			if p.peekTokenIs(token.IDENTFIER) {
+				p.nextToken()
+				pattern.Rest = p.newIdentifier()
+			}
+			if !p.peekTokenIs(token.RBRACKET) {
+				p.errorAt(p.peekToken, "the rest element must be the last element of an array pattern")
+				return nil
+			}
+			break
+		}
+
+		el := element()
+		if el == nil {
+			return nil
+		}
+		pattern.Elements = append(pattern.Elements, el)
+
+		if !p.peekTokenIs(token.RBRACKET) && !p.expectPeek(token.COMMA) {
+			return nil
+		}
+	}
+	p.nextToken() // The closing ']'
+
+	p.finishNode(pattern, pattern.Token.Pos)
+	return pattern
+}
+
+// parseHashPattern parses {"key": pattern, ...}. A bare name is shorthand for
+// a string key, so {name} binds the "name" key to name, and {age: years}
+// binds the "age" key to years.
+func (p *Parser) parseHashPattern(element func() ast.Pattern) ast.Pattern {
+	pattern := &ast.HashPattern{Token: p.currentToken}
+
+	for !p.peekTokenIs(token.RBRACE) {
 		p.nextToken()
-		identifier := &ast.Identifier{Token: p.currentToken, Value: p.currentToken.Literal}
-		identifiers = append(identifiers, identifier)
+		pair := &ast.HashPatternPair{}
+
+		switch p.currentToken.Type {
+		case token.IDENTFIER:
+			key := &ast.StringLiteral{Token: p.currentToken, Value: p.currentToken.Literal}
+			p.finishNode(key, key.Token.Pos)
+			pair.Key = key
+			if !p.peekTokenIs(token.COLON) {
+				pair.Value = element()
+				if pair.Value == nil {
+					return nil
+				}
+			}
+		case token.STRING, token.INT, token.TRUE, token.FALSE:
+			pair.Key = p.parseExpression(Prefix)
+		default:
+			p.errorAt(p.currentToken, "expected a hash pattern key, got %s instead", p.currentToken.Type)
+			return nil
+		}
+
+		if pair.Value == nil {
+			if !p.expectPeek(token.COLON) {
+				return nil
+			}
+			p.nextToken()
+			pair.Value = element()
+			if pair.Value == nil {
+				return nil
+			}
+		}
+		pattern.Pairs = append(pattern.Pairs, pair)
+
+		if !p.peekTokenIs(token.RBRACE) && !p.expectPeek(token.COMMA) {
+			return nil
+		}
 	}
+	p.nextToken() // The closing '}'
+
+	p.finishNode(pattern, pattern.Token.Pos)
+	return pattern
+}
+
+func (p *Parser) parseWhileExpression() ast.Expression {
+	expression := &ast.WhileExpression{Token: p.currentToken}
+
+	if !p.expectPeek(token.LPAREN) {
+		return nil
+	}
+
+	// get condition
+	p.nextToken()
+	expression.Condition = p.parseExpression(Lowest)
 
 	if !p.expectPeek(token.RPAREN) {
 		return nil
 	}
 
-	return identifiers
+	if !p.expectPeek(token.LBRACE) {
+		return nil
+	}
+
+	expression.Consequence = p.parseBlockStatement()
+
+	return expression
+}
+
+// parseFunctionParameters parses the parameters of a function literal along
+// with their defaults, as in fn(x, y = 10), and a final rest parameter, as in
+// fn(first, ...rest). Defaults gets an entry for every parameter, which is nil
+// for parameters without a default.
+func (p *Parser) parseFunctionParameters(lit *ast.FunctionLiteral) {
+	if p.peekTokenIs(token.RPAREN) {
+		p.nextToken()
+		return
+	}
+
+	for {
+		if p.peekTokenIs(token.ELLIPSIS) {
+			p.nextToken()
+			if !p.expectPeek(token.IDENTFIER) {
+				return
+			}
+			lit.Rest = p.newIdentifier()
+			if !p.peekTokenIs(token.RPAREN) {
+				p.errorAt(p.peekToken, "the rest parameter must be the last parameter")
+				return
+			}
+			break
+		}
+
+		if !p.expectPeek(token.IDENTFIER) {
+			return
+		}
+		lit.Parameters = append(lit.Parameters, p.newIdentifier())
+
+		var value ast.Expression
+		if p.peekTokenIs(token.ASSIGN) {
+			p.nextToken()
+			p.nextToken()
+			value = p.parseExpression(Lowest)
+		}
+		lit.Defaults = append(lit.Defaults, value)
+
+		if !p.peekTokenIs(token.COMMA) {
+			break
+		}
+		p.nextToken()
+	}
+
+	p.expectPeek(token.RPAREN)
 }
 
 func (p *Parser) parseStringLiteral() ast.Expression {
 	return &ast.StringLiteral{Token: p.currentToken, Value: p.currentToken.Literal}
 }
 
+func (p *Parser) parseInterpolatedString() ast.Expression {
+	str := &ast.InterpolatedString{Token: p.currentToken}
+
+	for {
+		if p.currentToken.Literal != "" {
+			part := &ast.StringLiteral{Token: p.currentToken, Value: p.currentToken.Literal}
+			p.finishNode(part, p.currentToken.Pos)
+			str.Parts = append(str.Parts, part)
+		}
+		if p.currentTokenIs(token.STRINGTAIL) {
+			return str
+		}
+
+		if p.peekTokenIs(token.STRINGMID) || p.peekTokenIs(token.STRINGTAIL) {
+			p.errorAt(p.peekToken, "empty expression in string interpolation")
+			return nil
+		}
+		p.nextToken()
+		str.Parts = append(str.Parts, p.parseExpression(Lowest))
+
+		if !p.peekTokenIs(token.STRINGMID) && !p.peekTokenIs(token.STRINGTAIL) {
+			p.errorAt(p.peekToken, "expected } to close string interpolation, got %s instead", p.peekToken.Type)
+			return nil
+		}
+		p.nextToken()
+	}
+}
+
 func (p *Parser) parseArrayLiteral() ast.Expression {
 	array := &ast.ArrayLiteral{Token: p.currentToken}
 
@@ -524,12 +1199,12 @@ func (p *Parser) parseExpressionList(end token.TokenType) []ast.Expression {
 	}
 
 	p.nextToken()
-	list = append(list, p.parseExpression(Lowest))
+	list = append(list, p.parseElement())
 
 	for p.peekTokenIs(token.COMMA) {
 		p.nextToken()
 		p.nextToken()
-		list = append(list, p.parseExpression(Lowest))
+		list = append(list, p.parseElement())
 	}
 
 	if !p.expectPeek(end) {
@@ -539,6 +1214,28 @@ func (p *Parser) parseExpressionList(end token.TokenType) []ast.Expression {
 	return list
 }
 
+// parseElement parses an element of an array literal or an argument of a
+// call, either of which may be spread
+func (p *Parser) parseElement() ast.Expression {
+	if p.currentTokenIs(token.ELLIPSIS) {
+		return p.parseSpreadExpression()
+	}
+	return p.parseExpression(Lowest)
+}
+
+func (p *Parser) parseSpreadExpression() ast.Expression {
+	spread := &ast.SpreadExpression{Token: p.currentToken}
+
+	p.nextToken()
+	spread.Value = p.parseExpression(Lowest)
+	if spread.Value == nil {
+		return nil
+	}
+
+	p.finishNode(spread, spread.Token.Pos)
+	return spread
+}
+
 func (p *Parser) parseIndexExpression(left ast.Expression) ast.Expression {
 	exp := &ast.IndexExpression{Token: p.currentToken, Left: left}
 
@@ -558,6 +1255,14 @@ func (p *Parser) parseHashLiteral() ast.Expression {
 
 	for !p.peekTokenIs(token.RBRACE) {
 		p.nextToken()
+		if p.currentTokenIs(token.ELLIPSIS) {
+			hash.Entries = append(hash.Entries, p.parseSpreadExpression())
+			if !p.peekTokenIs(token.RBRACE) && !p.expectPeek(token.COMMA) {
+				return nil
+			}
+			continue
+		}
+
 		key := p.parseExpression(Lowest)
 
 		if !p.expectPeek(token.COLON) {
@@ -568,6 +1273,7 @@ func (p *Parser) parseHashLiteral() ast.Expression {
 		value := p.parseExpression(Lowest)
 
 		hash.Pairs[key] = value
+		hash.Entries = append(hash.Entries, key)
 
 		if !p.peekTokenIs(token.RBRACE) && !p.expectPeek(token.COMMA) {
 			return nil
@@ -590,6 +1296,65 @@ func (p *Parser) parseInfixExpression(left ast.Expression) ast.Expression {
 		Left:     left,
 	}
 
+	precedence := p.currentPrecedence()
+	if p.currentTokenIs(token.POW) {
+		precedence-- // ** is right-associative: 2 ** 3 ** 2 is 2 ** (3 ** 2)
+	}
+	p.nextToken()
+	expression.Right = p.parseExpression(precedence)
+	return expression
+}
+
+func (p *Parser) parseAssignExpression(left ast.Expression) ast.Expression {
+	expression := &ast.AssignExpression{
+		Token:    p.currentToken,
+		Operator: p.currentToken.Literal,
+		Target:   left,
+	}
+
+	if left == nil {
+		return nil
+	}
+	switch left.(type) {
+	case *ast.Identifier, *ast.IndexExpression:
+	default:
+		p.errorAt(p.currentToken, "cannot assign to %s", left.String())
+		return nil
+	}
+
+	// Assignment is right-associative: a = b = 1 is a = (b = 1)
+	p.nextToken()
+	expression.Value = p.parseExpression(Assign - 1)
+	return expression
+}
+
+// parseRangeExpression parses a..b or a..<b, followed by an optional step.
+// step is not a keyword, so it can still be used as a name elsewhere.
+func (p *Parser) parseRangeExpression(left ast.Expression) ast.Expression {
+	expression := &ast.RangeExpression{
+		Token:     p.currentToken,
+		From:      left,
+		Inclusive: p.currentTokenIs(token.DOTDOT),
+	}
+
+	p.nextToken()
+	expression.To = p.parseExpression(Range)
+
+	if p.peekTokenIs(token.IDENTFIER) && p.peekToken.Literal == "step" {
+		p.nextToken()
+		p.nextToken()
+		expression.Step = p.parseExpression(Range)
+	}
+	return expression
+}
+
+func (p *Parser) parseLogicalExpression(left ast.Expression) ast.Expression {
+	expression := &ast.LogicalExpression{
+		Token:    p.currentToken,
+		Operator: p.currentToken.Literal,
+		Left:     left,
+	}
+
 	precedence := p.currentPrecedence()
 	p.nextToken()
 	expression.Right = p.parseExpression(precedence)
@@ -598,15 +1363,69 @@ func (p *Parser) parseInfixExpression(left ast.Expression) ast.Expression {
 
 func (p *Parser) parseCallExpression(function ast.Expression) ast.Expression {
 	exp := &ast.CallExpression{Token: p.currentToken, Function: function}
-	exp.Arguments = p.parseExpressionList(token.RPAREN)
+	if !p.parseCallArguments(exp) {
+		return nil
+	}
 	return exp
 }
 
+// parseCallArguments parses the arguments of a call. Arguments written as
+// name: value are passed by name, and must come after the positional ones.
+// It reports whether the arguments were parsed without error.
+func (p *Parser) parseCallArguments(exp *ast.CallExpression) bool {
+	if p.peekTokenIs(token.RPAREN) {
+		p.nextToken()
+		return true
+	}
+
+	for {
+		p.nextToken()
+
+		if p.currentTokenIs(token.IDENTFIER) && p.peekTokenIs(token.COLON) {
+			named := &ast.NamedArgument{Name: p.newIdentifier()}
+			p.nextToken()
+			p.nextToken()
+			named.Value = p.parseExpression(Lowest)
+			p.finishNode(named, named.Name.Token.Pos)
+			exp.NamedArguments = append(exp.NamedArguments, named)
+		} else if len(exp.NamedArguments) > 0 {
+			p.errorAt(p.currentToken, "positional argument after named argument")
+			return false
+		} else {
+			exp.Arguments = append(exp.Arguments, p.parseElement())
+		}
+
+		if !p.peekTokenIs(token.COMMA) {
+			break
+		}
+		p.nextToken()
+	}
+
+	return p.expectPeek(token.RPAREN)
+}
+
 // Token
 
 func (p *Parser) nextToken() {
 	p.currentToken = p.peekToken
-	p.peekToken = p.l.NextToken()
+	if len(p.lookahead) > 0 {
+		p.peekToken = p.lookahead[0]
+		p.lookahead = p.lookahead[1:]
+	} else {
+		p.peekToken = p.l.NextToken()
+	}
+}
+
+// peekN returns the token n places after the current one, so peekN(1) is the
+// peek token. Tokens read ahead are kept until nextToken reaches them.
+func (p *Parser) peekN(n int) token.Token {
+	if n == 1 {
+		return p.peekToken
+	}
+	for len(p.lookahead) < n-1 {
+		p.lookahead = append(p.lookahead, p.l.NextToken())
+	}
+	return p.lookahead[n-2]
 }
 
 func (p *Parser) expectPeek(t token.TokenType) bool {
@@ -636,6 +1455,11 @@ func (p *Parser) peekTokenIs(t token.TokenType) bool {
 	return p.peekToken.Type == t
 }
 
+// finishNode records on node the span from start to the end of the current token
+func (p *Parser) finishNode(node ast.Node, start token.Position) {
+	node.SetSpan(token.Span{Start: start, End: p.currentToken.End})
+}
+
 // Precedence
 
 func (p *Parser) peekPrecedence() int {
@@ -656,14 +1480,65 @@ func (p *Parser) currentPrecedence() int {
 
 // Error
 
+// errorAt reports an error diagnostic pointing at tok. Only the first error of
+// a statement is reported, the rest are usually a consequence of it.
+func (p *Parser) errorAt(tok token.Token, format string, a ...interface{}) *diagnostic.Diagnostic {
+	d := diagnostic.New(p.l.File(), token.Span{Start: tok.Pos, End: tok.End}, format, a...)
+	if p.panicking {
+		return d
+	}
+	p.panicking = true
+	p.diagnostics = append(p.diagnostics, d)
+	return d
+}
+
+// synchronize skips the rest of a statement that failed to parse. It stops on
+// the statement's ';', or right before a '}' or a keyword that starts a new
+// statement, so the caller's next token is where parsing can resume.
+func (p *Parser) synchronize() {
+	p.panicking = false
+
+	for !p.currentTokenIs(token.EOF) && !p.currentTokenIs(token.SEMICOLON) {
+		switch p.peekToken.Type {
+		case token.LET, token.RETURN, token.IF, token.WHILE, token.FOR, token.RBRACE, token.EOF:
+			return
+		}
+		p.nextToken()
+	}
+}
+
 func (p *Parser) peekError(t token.TokenType) {
-	msg := fmt.Sprintf("expected next token to be %s, got %s instead", t, p.peekToken.Type)
-	p.errors = append(p.errors, msg)
+	if p.peekTokenIs(token.ILLEGAL) {
+		p.illegalTokenError(p.peekToken)
+		return
+	}
+
+	d := p.errorAt(p.peekToken, "expected next token to be %s, got %s instead", t, p.peekToken.Type)
+
+	switch t {
+	case token.RPAREN, token.RBRACE, token.RBRACKET:
+		d.Hint = fmt.Sprintf("add the missing `%s`", t)
+	case token.IDENTFIER:
+		if name := token.LookupTokenIdentifier(p.peekToken.Type); name != "" {
+			d.Hint = fmt.Sprintf("`%s` is a keyword and cannot be used as a name", name)
+		}
+	}
+}
+
+// illegalTokenError reports an ILLEGAL token, unless the lexer already
+// reported why it is illegal
+func (p *Parser) illegalTokenError(tok token.Token) {
+	for _, d := range p.l.Errors() {
+		if d.Span.Start == tok.Pos {
+			p.panicking = true
+			return
+		}
+	}
+	p.errorAt(tok, "illegal character %q", tok.Literal)
 }
 
 func (p *Parser) noPrefixParseFnError(t token.TokenType) {
-	msg := fmt.Sprintf("no prefix parse function for %s found", t)
-	p.errors = append(p.errors, msg)
+	p.errorAt(p.currentToken, "no prefix parse function for %s found", t)
 }
 
 // Operators
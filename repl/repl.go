@@ -7,6 +7,7 @@ import (
 	"os"
 	"strings"
 
+	"github.com/anilang-official/AniLang/diagnostic"
 	"github.com/anilang-official/AniLang/evaluator"
 	"github.com/anilang-official/AniLang/lexer"
 	"github.com/anilang-official/AniLang/object"
@@ -57,12 +58,12 @@ func Start(in io.Reader, out io.Writer) {
 			continue
 		}
 
-		l := lexer.New(line)
+		l := lexer.NewFile("<repl>", line)
 		p := parser.New(l)
 
 		program := p.ParseProgram()
-		if len(p.Errors()) != 0 {
-			printParserErrors(out, p.Errors())
+		if len(p.Diagnostics()) != 0 {
+			printDiagnostics(out, line, p.Diagnostics())
 			continue
 		}
 
@@ -74,10 +75,11 @@ func Start(in io.Reader, out io.Writer) {
 	}
 }
 
-func printParserErrors(out io.Writer, errors []string) {
-	for _, msg := range errors {
-		io.WriteString(out, "\t"+msg+"\n")
-	}
+// printDiagnostics renders diagnostics against the source they were found in,
+// in colour when out is a terminal
+func printDiagnostics(out io.Writer, source string, diagnostics []*diagnostic.Diagnostic) {
+	printer := diagnostic.NewPrinter(out, source, diagnostic.IsTerminal(out))
+	printer.PrintAll(diagnostics)
 }
 
 func ReplFile(filename string, out io.Writer) {
@@ -89,12 +91,12 @@ func ReplFile(filename string, out io.Writer) {
 		return
 	}
 
-	l := lexer.New(string(fileContent))
+	l := lexer.NewFile(filename, string(fileContent))
 	p := parser.New(l)
 
 	program := p.ParseProgram()
-	if len(p.Errors()) != 0 {
-		printParserErrors(out, p.Errors())
+	if len(p.Diagnostics()) != 0 {
+		printDiagnostics(out, string(fileContent), p.Diagnostics())
 		return
 	}
 
@@ -0,0 +1,85 @@
+package test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/anilang-official/AniLang/diagnostic"
+	"github.com/anilang-official/AniLang/lexer"
+	"github.com/anilang-official/AniLang/parser"
+	"github.com/anilang-official/AniLang/token"
+)
+
+func TestParserDiagnostics(t *testing.T) {
+	input := "let x = 5;\nlet y = add(1, 2;"
+
+	l := lexer.NewFile("main.ani", input)
+	p := parser.New(l)
+	p.ParseProgram()
+
+	diagnostics := p.Diagnostics()
+	if len(diagnostics) == 0 {
+		t.Fatalf("expected parser diagnostics, got none")
+	}
+
+	d := diagnostics[0]
+	if d.Severity != diagnostic.Error {
+		t.Errorf("d.Severity wrong. expected=%s, got=%s", diagnostic.Error, d.Severity)
+	}
+	if d.Message != "expected next token to be ), got ; instead" {
+		t.Errorf("d.Message wrong. got=%q", d.Message)
+	}
+	if d.Error() != "main.ani:2:17: error: expected next token to be ), got ; instead" {
+		t.Errorf("d.Error() wrong. got=%q", d.Error())
+	}
+	if d.Hint == "" {
+		t.Errorf("d.Hint is empty")
+	}
+}
+
+func TestDiagnosticPrinter(t *testing.T) {
+	source := "let x = 5;\n\tlet y = foo bar;"
+	d := diagnostic.New("main.ani", spanOf(2, 14, 2, 17), "unexpected identifier")
+	d.Hint = "add a `;`"
+
+	var out bytes.Buffer
+	diagnostic.NewPrinter(&out, source, false).Print(d)
+
+	expected := "error: unexpected identifier\n" +
+		" --> main.ani:2:14\n" +
+		"  |\n" +
+		"2 | \tlet y = foo bar;\n" +
+		"  | \t            ^^^\n" +
+		"  = hint: add a `;`\n"
+
+	if out.String() != expected {
+		t.Errorf("printed diagnostic wrong.\nexpected=%q\ngot=     %q", expected, out.String())
+	}
+}
+
+func TestDiagnosticPrinterUnicode(t *testing.T) {
+	source := `let café = "😀" @;`
+	l := lexer.New(source)
+	p := parser.New(l)
+	p.ParseProgram()
+
+	var out bytes.Buffer
+	diagnostic.NewPrinter(&out, source, false).PrintAll(p.Diagnostics())
+
+	expected := "error: illegal character \"@\"\n" +
+		" --> <input>:1:16\n" +
+		"  |\n" +
+		"1 | let café = \"😀\" @;\n" +
+		"  |                ^\n"
+
+	if out.String() != expected {
+		t.Errorf("printed diagnostic wrong.\nexpected=%q\ngot=     %q", expected, out.String())
+	}
+}
+
+func spanOf(startLine, startColumn, endLine, endColumn int) token.Span {
+	return token.Span{
+		Start: token.Position{Line: startLine, Column: startColumn},
+		End:   token.Position{Line: endLine, Column: endColumn},
+	}
+}
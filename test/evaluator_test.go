@@ -83,6 +83,24 @@ func TestEvalBooleanExpression(t *testing.T) {
 		{"(1 < 2) == false", false},
 		{"(1 > 2) == true", false},
 		{"(1 > 2) == false", true},
+		{"1 <= 2", true},
+		{"2 <= 2", true},
+		{"3 <= 2", false},
+		{"1 >= 2", false},
+		{"2 >= 2", true},
+		{"2.5 >= 2", true},
+		{"9223372036854775808 <= 9223372036854775807", false},
+		{`"a" == "a"`, true},
+		{`"a" != "a"`, false},
+		{`"a" == "b"`, false},
+		{`"a" < "b"`, true},
+		{`"apple" < "apricot"`, true},
+		{`"b" > "abc"`, true},
+		{`"ab" <= "ab"`, true},
+		{`"ab" >= "abc"`, false},
+		{`"Z" < "a"`, true},
+		{`"" < "a"`, true},
+		{`"a" + "b" == "ab"`, true},
 	}
 	for _, tt := range tests {
 		evaluated := testEval(tt.input)
@@ -104,6 +122,41 @@ func testBooleanObject(t *testing.T, obj object.Object, expected bool) bool {
 	return true
 }
 
+func TestLogicalOperators(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected bool
+	}{
+		{"true && true", true},
+		{"true && false", false},
+		{"false && true", false},
+		{"false || true", true},
+		{"false || false", false},
+		{"1 < 2 && 2 < 3", true},
+		{"1 > 2 || 2 > 3", false},
+		{"null || 5", true},
+		{`"" && null`, false},
+		{"let x = null; x != null && x[0] > 1", false},
+		{"let x = [2]; x != null && x[0] > 1", true},
+		{"true || missing", true},
+		{"false && missing()", false},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		testBooleanObject(t, evaluated, tt.expected)
+	}
+
+	evaluated := testEval("true && missing")
+	errObj, ok := evaluated.(*object.Error)
+	if !ok {
+		t.Fatalf("object is not Error. got=%T (%+v)", evaluated, evaluated)
+	}
+	if errObj.Message != "identifier not found: missing" {
+		t.Errorf("wrong error message. got=%q", errObj.Message)
+	}
+}
+
 func TestBangOperator(t *testing.T) {
 	tests := []struct {
 		input    string
@@ -177,6 +230,10 @@ func TestReturnStatements(t *testing.T) {
 			`,
 			10,
 		},
+		{"let f = fn() { for (let i = 0; i < 10; let i++) { if (i == 3) { sayonara 42; } }; 99 }; f()", 42},
+		{"let f = fn() { let i = 0; while (i < 10) { if (i == 3) { sayonara 42; }; let i += 1; }; 99 }; f()", 42},
+		{"let f = fn() { for (i in 0..<10) { if (i == 3) { sayonara 42; } }; 99 }; f()", 42},
+		{"let i = 0; while (true) { sayonara 7; }; 99", 7},
 	}
 	for _, tt := range tests {
 		evaluated := testEval(tt.input)
@@ -267,6 +324,121 @@ func TestLetStatements(t *testing.T) {
 	}
 }
 
+func TestAssignExpressions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"let x = 1; x = 5; x", 5},
+		{"let x = 1; x = 5", 5},
+		{"let x = 1; x += 4; x", 5},
+		{"let x = 10; x %= 4; x", 2},
+		{"let a = 1; let b = 2; a = b = 7; a + b", 14},
+		{`let s = "a"; s += "b"; s`, "ab"},
+		{`let s = "a"; let s += "b"; s`, "ab"},
+		{`let s = "a"; let f = fn() { let s += "b" }; f(); s`, "ab"},
+		{"let x = 1; let f = fn() { x = 2 }; f(); x", 2},
+		{"let x = 1; let f = fn(x) { x = 2 }; f(5); x", 1},
+		{"let count = 0; let inc = fn() { count += 1 }; inc(); inc(); count", 2},
+		{"let makeCounter = fn() { let n = 0; fn() { n = n + 1 } }; let c = makeCounter(); c(); c(); c()", 3},
+		{"let total = 0; let add = fn(n) { let total += n }; add(3); add(4); total", 7},
+		{"let i = 0; let inc = fn() { let i++ }; inc(); inc(); i", 2},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		switch expected := tt.expected.(type) {
+		case int:
+			testIntegerObject(t, evaluated, int64(expected))
+		case string:
+			if evaluated.Inspect() != expected {
+				t.Errorf("%q wrong result. expected=%s, got=%s", tt.input, expected, evaluated.Inspect())
+			}
+		}
+	}
+}
+
+func TestAssignExpressionErrors(t *testing.T) {
+	tests := []struct {
+		input           string
+		expectedMessage string
+	}{
+		{`let a = [1]; a -= 1`, "type mismatch: ARRAY - INTEGER"},
+		{`let a = [1]; let a -= 1`, "type mismatch: ARRAY - INTEGER"},
+		{`let s = "a"; let s -= "b"`, "unknown operator: STRING - STRING"},
+		{"y = 3", "assignment to undeclared variable: y"},
+		{"y += 3", "assignment to undeclared variable: y"},
+		{"let x = 1; x /= 0", "division by zero"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		errObj, ok := evaluated.(*object.Error)
+		if !ok {
+			t.Errorf("%q: no error object returned. got=%T(%+v)", tt.input, evaluated, evaluated)
+			continue
+		}
+		if errObj.Message != tt.expectedMessage {
+			t.Errorf("%q: wrong error message. expected=%q, got=%q", tt.input, tt.expectedMessage, errObj.Message)
+		}
+	}
+}
+
+func TestIndexAssignment(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"let a = [1, 2, 3]; a[0] = 10; a", "[10, 2, 3]"},
+		{"let a = [1, 2, 3]; a[2] += 5; a", "[1, 2, 8]"},
+		{"let a = [1, 2, 3]; a[1] = 7", 7},
+		{`let h = {"a": 1}; h["b"] = 2; h["a"] + h["b"]`, 3},
+		{`let h = {"a": 1}; h["a"] *= 7; h["a"]`, 7},
+		{"let g = [[0, 0], [0, 0]]; g[1][0] = 9; g", "[[0, 0], [9, 0]]"},
+		{"let a = [1]; let b = a; b[0] = 2; a", "[2]"},
+		{`let a = [1]; let set = fn(arr) { arr[0] = "x" }; set(a); a`, "[x]"},
+		{"let a = [1, 2, 3]; let i = 0; a[i = 1] = 5; a", "[1, 5, 3]"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		switch expected := tt.expected.(type) {
+		case int:
+			testIntegerObject(t, evaluated, int64(expected))
+		case string:
+			if evaluated.Inspect() != expected {
+				t.Errorf("%q wrong result. expected=%s, got=%s", tt.input, expected, evaluated.Inspect())
+			}
+		}
+	}
+}
+
+func TestIndexAssignmentErrors(t *testing.T) {
+	tests := []struct {
+		input           string
+		expectedMessage string
+	}{
+		{"let a = [1, 2, 3]; a[3] = 1", "index out of range: 3 (length 3)"},
+		{"let a = [1, 2, 3]; a[-1] = 1", "index out of range: -1 (length 3)"},
+		{`let a = [1, 2, 3]; a["x"] = 1`, "array index must be INTEGER, got STRING"},
+		{`let h = {}; h["a"] += 1`, "key not found: a"},
+		{`let h = {}; h[fn(x) { x }] = 1`, "unusable as hash key: FUNCTION"},
+		{`let s = "abc"; s[0] = "x"`, "index assignment not supported: STRING"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		errObj, ok := evaluated.(*object.Error)
+		if !ok {
+			t.Errorf("%q: no error object returned. got=%T(%+v)", tt.input, evaluated, evaluated)
+			continue
+		}
+		if errObj.Message != tt.expectedMessage {
+			t.Errorf("%q: wrong error message. expected=%q, got=%q", tt.input, tt.expectedMessage, errObj.Message)
+		}
+	}
+}
+
 func TestFunctionObject(t *testing.T) {
 	input := "fn(x) { x + 2; };"
 	evaluated := testEval(input)
@@ -326,6 +498,68 @@ func TestStringLiteral(t *testing.T) {
 	}
 }
 
+func TestStringEscapes(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`"a\nb"`, "a\nb"},
+		{`"tab\there"`, "tab\there"},
+		{`"say \"hi\""`, `say "hi"`},
+		{`"back\\slash"`, `back\slash`},
+		{`"\x41\x62"`, "Ab"},
+		{`"\u{48}\u{1F600}"`, "H\U0001F600"},
+	}
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		str, ok := evaluated.(*object.String)
+		if !ok {
+			t.Fatalf("object is not String. got=%T (%+v)", evaluated, evaluated)
+		}
+		if str.Value != tt.expected {
+			t.Errorf("String has wrong value. expected=%q, got=%q", tt.expected, str.Value)
+		}
+		if str.Inspect() != tt.expected || str.Value != tt.expected {
+			t.Errorf("Inspect changed the string. got=%q", str.Value)
+		}
+	}
+
+	testIntegerObject(t, testEval(`len("a\nb")`), 3)
+}
+
+func TestStringInterpolation(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`let name = "Ani"; "Hello ${name}!"`, "Hello Ani!"},
+		{`let items = [1, 2]; "${len(items)} items: ${items}"`, "2 items: [1, 2]"},
+		{`"${1 + 2}${true}${2.5}"`, "3true2.5"},
+		{`let x = "in"; "out ${"mid ${x}"} out"`, "out mid in out"},
+		{`"cost: \${price}"`, "cost: ${price}"},
+		{`let f = fn() { let a = 1; }; "${f()}"`, "null"},
+	}
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		str, ok := evaluated.(*object.String)
+		if !ok {
+			t.Fatalf("object is not String. got=%T (%+v)", evaluated, evaluated)
+		}
+		if str.Value != tt.expected {
+			t.Errorf("String has wrong value. expected=%q, got=%q", tt.expected, str.Value)
+		}
+	}
+
+	evaluated := testEval(`"value: ${missing}"`)
+	errObj, ok := evaluated.(*object.Error)
+	if !ok {
+		t.Fatalf("object is not Error. got=%T (%+v)", evaluated, evaluated)
+	}
+	if errObj.Message != "identifier not found: missing" {
+		t.Errorf("wrong error message. got=%q", errObj.Message)
+	}
+}
+
 func TestStringConcatenation(t *testing.T) {
 	input := `"Hello" + " " + "World!"`
 	evaluated := testEval(input)
@@ -347,7 +581,16 @@ func TestBuiltinFunctions(t *testing.T) {
 		{`len("four")`, 4},
 		{`len("hello world")`, 11},
 		{`len(1)`, "argument to `len` not supported, got INTEGER"},
-		{`len("one", "two")`, "wrong number of arguments. got=2, want=1"},
+		{`len("one", "two")`, "wrong number of arguments to len(value): expected 1, got 2"},
+		{`len("naïve 😀")`, 7},
+		{`byteLen("naïve 😀")`, 11},
+		{`byteLen("")`, 0},
+		{`byteAt("é", 0)`, 195},
+		{`byteAt("é", 2)`, "index out of range"},
+		{`byteAt("é", 99999999999999999999)`, "index out of range"},
+		{`charAt("abc", 99999999999999999999)`, "index out of range"},
+		{`charAt("abc", -99999999999999999999)`, "index out of range"},
+		{`byteLen(1)`, "argument to `byteLen` must be STRING, got INTEGER"},
 	}
 	for _, tt := range tests {
 		evaluated := testEval(tt.input)
@@ -369,6 +612,28 @@ func TestBuiltinFunctions(t *testing.T) {
 	}
 }
 
+func TestCharAt(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`charAt("abc", 1)`, "b"},
+		{`charAt("naïve", 2)`, "ï"},
+		{`charAt("a😀b", 2)`, "b"},
+		{`charAt("a😀b", 1)`, "😀"},
+	}
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		str, ok := evaluated.(*object.String)
+		if !ok {
+			t.Fatalf("object is not String. got=%T (%+v)", evaluated, evaluated)
+		}
+		if str.Value != tt.expected {
+			t.Errorf("%s: wrong value. expected=%q, got=%q", tt.input, tt.expected, str.Value)
+		}
+	}
+}
+
 func TestArrayLiterals(t *testing.T) {
 	input := "[1, 2 * 2, 3 + 3]"
 	evaluated := testEval(input)
@@ -510,6 +775,22 @@ func TestHashIndexExpressions(t *testing.T) {
 			`{false: 5}[false]`,
 			5,
 		},
+		{
+			`{1: 5}[1.0]`,
+			5,
+		},
+		{
+			`{2.0: 5}[2]`,
+			5,
+		},
+		{
+			`{18446744073709551616: 5}[18446744073709551616.0]`,
+			5,
+		},
+		{
+			`{1: 5}[1.5]`,
+			nil,
+		},
 	}
 	for _, tt := range tests {
 		evaluated := testEval(tt.input)
@@ -541,21 +822,822 @@ func TestForExpressionFunction(t *testing.T) {
 	}
 }
 
-func TestWhileExpressionFunction(t *testing.T) {
-	test := []struct {
+func TestForInExpression(t *testing.T) {
+	tests := []struct {
 		input    string
 		expected interface{}
 	}{
-		{
-			"let i = 0; while (i < 10) { let i += 1; }",
-			evaluator.NULL,
-		},
+		{"let s = 0; for (x in [1, 2, 3]) { s += x }; s", 6},
+		{"let s = 0; for (i, x in [5, 6, 7]) { s += i * x }; s", 6 + 14},
+		{`let s = ""; for (c in "héllo") { s = c + s }; s`, "olléh"},
+		{`let s = ""; for (i, c in "ab") { s += "${i}${c}" }; s`, "0a1b"},
+		{`let s = ""; for (k in {"b": 2, "a": 1, "c": 3}) { s += k }; s`, "abc"},
+		{`let s = ""; for (k, v in {"b": 2, "a": 1}) { s += "${k}=${v};" }; s`, "a=1;b=2;"},
+		{`let s = ""; for (k in {"x": 0, 2: 0, true: 0, 1: 0}) { s += "${k}," }; s`, "true,1,2,x,"},
+		{"let s = 0; for (x in [1, 2, 3, 4]) { if (x == 3) { yamete }; s += x }; s", 3},
+		{"let s = 0; for (x in [1, 2, 3, 4]) { if (x % 2 == 0) { continue }; s += x }; s", 4},
+		{"let f = fn(xs) { for (x in xs) { if (x > 1) { sayonara x } }; sayonara -1 }; f([0, 5, 7])", 5},
+		{"let fns = []; for (x in [1, 2]) { fns = push(fns, fn() { x }) }; fns[0]() + fns[1]() * 10", 21},
+		{"for (x in []) { x }", evaluator.NULL},
+		{"let s = 0; for (let i = 0; i < 4; i += 1) { s += i }; s", 6},
 	}
 
-	for _, tt := range test {
+	for _, tt := range tests {
 		evaluated := testEval(tt.input)
-		if evaluated != tt.expected {
-			t.Errorf("expected %v, got %v", tt.expected, evaluated)
+		switch expected := tt.expected.(type) {
+		case int:
+			testIntegerObject(t, evaluated, int64(expected))
+		case string:
+			if evaluated.Inspect() != expected {
+				t.Errorf("%q wrong result. expected=%s, got=%s", tt.input, expected, evaluated.Inspect())
+			}
+		default:
+			if evaluated != expected {
+				t.Errorf("%q wrong result. expected=%v, got=%v", tt.input, expected, evaluated)
+			}
+		}
+	}
+}
+
+func TestForInExpressionErrors(t *testing.T) {
+	tests := []struct {
+		input           string
+		expectedMessage string
+	}{
+		{"for (x in [1]) { x }; x", "identifier not found: x"},
+		{"for (x in 5) { x }", "cannot iterate over INTEGER"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		errObj, ok := evaluated.(*object.Error)
+		if !ok {
+			t.Errorf("%q: no error object returned. got=%T(%+v)", tt.input, evaluated, evaluated)
+			continue
+		}
+		if errObj.Message != tt.expectedMessage {
+			t.Errorf("%q: wrong error message. expected=%q, got=%q", tt.input, tt.expectedMessage, errObj.Message)
+		}
+	}
+}
+
+func TestRanges(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"1..10", "1..10"},
+		{"0..<10 step 2", "0..<10 step 2"},
+		{"len(1..10)", 10},
+		{"len(1..<10)", 9},
+		{"len(0..<10 step 3)", 4},
+		{"len(0..10 step 5)", 3},
+		{"len(10..1)", 0},
+		{"len(10..1 step -1)", 10},
+		{"(1..10)[0]", 1},
+		{"(1..10)[9]", 10},
+		{"(1..10)[10]", evaluator.NULL},
+		{"(0..<10 step 3)[2]", 6},
+		{"toArray(1..5)", "[1, 2, 3, 4, 5]"},
+		{"toArray(0..<10 step 3)", "[0, 3, 6, 9]"},
+		{"toArray(10..1 step -3)", "[10, 7, 4, 1]"},
+		{"toArray(5..1)", "[]"},
+		{`toArray("héy")`, "[h, é, y]"},
+		{"let s = 0; for (i in 1..100) { s += i }; s", 5050},
+		{"let s = 0; for (i, x in 10..<13) { s += i * x }; s", 11 + 24},
+		{"let n = 3; toArray(1..n + 1)", "[1, 2, 3, 4]"},
+		{"5 in 1..10", true},
+		{"10 in 1..<10", false},
+		{"4 in 0..10 step 2", true},
+		{"5 in 0..10 step 2", false},
+		{"-3 in 0..-9 step -3", true},
+		{"2 in [1, 2, 3]", true},
+		{"2.0 in [1, 2, 3]", true},
+		{`"b" in ["a", "c"]`, false},
+		{`"k" in {"k": 1}`, true},
+		{`"v" in {"k": "v"}`, false},
+		{`"ell" in "hello"`, true},
+		{"len(0..<9223372036854775807)", 9223372036854775807},
+		{"5 in 0..<9223372036854775807", true},
+		{"9223372036854775806 in 0..<9223372036854775807", true},
+		{"-9223372036854775806 in 0..<-9223372036854775807 step -1", true},
+		{"len(9223372036854775807..-9223372036854775807 step -3)", 6148914691236517205},
+		{"(0..<9223372036854775807)[9223372036854775806]", 9223372036854775806},
+		{"len(toArray(1..4194304))", 4194304},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		switch expected := tt.expected.(type) {
+		case int:
+			testIntegerObject(t, evaluated, int64(expected))
+		case bool:
+			testBooleanObject(t, evaluated, expected)
+		case string:
+			if evaluated.Inspect() != expected {
+				t.Errorf("%q wrong result. expected=%s, got=%s", tt.input, expected, evaluated.Inspect())
+			}
+		default:
+			if evaluated != expected {
+				t.Errorf("%q wrong result. expected=%v, got=%v", tt.input, expected, evaluated)
+			}
+		}
+	}
+}
+
+func TestRangeErrors(t *testing.T) {
+	tests := []struct {
+		input           string
+		expectedMessage string
+	}{
+		{"1..10 step 0", "range step cannot be zero"},
+		{`1.."a"`, "range bounds and step must be INTEGER, got STRING"},
+		{"0..9223372036854775807", "range too long: 0..9223372036854775807"},
+		{"toArray(0..<9223372036854775807)", "range too large to expand: 0..<9223372036854775807 has 9223372036854775807 values, the limit is 4194304"},
+		{"1 in 5", "unknown operator: INTEGER in INTEGER"},
+		{`1 in "abc"`, "type mismatch: INTEGER in STRING"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		errObj, ok := evaluated.(*object.Error)
+		if !ok {
+			t.Errorf("%q: no error object returned. got=%T(%+v)", tt.input, evaluated, evaluated)
+			continue
+		}
+		if errObj.Message != tt.expectedMessage {
+			t.Errorf("%q: wrong error message. expected=%q, got=%q", tt.input, tt.expectedMessage, errObj.Message)
+		}
+	}
+}
+
+func TestMatchExpression(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`match (1) { 1 => "one", _ => "many" }`, "one"},
+		{`match (5) { 1 => "one", _ => "many" }`, "many"},
+		{`match ("b") { "a" => 1, "b" => 2 }`, 2},
+		{`match (2.0) { 2 => true, _ => false }`, true},
+		{`match (null) { null => 1, _ => 2 }`, 1},
+		{`match (-1) { -1 => "negative", _ => "other" }`, "negative"},
+		{`match (3) { 1 | 2 | 3 => "small", _ => "big" }`, "small"},
+		{`match ([1, 2]) { [a, 9] | [_, a] => a }`, 2},
+		{`let a = 0; match ([1, 2]) { [a, 9] | _ => a }`, 0},
+		{`match (7) { n => n * 2 }`, 14},
+		{`match (7) { n if n > 10 => "big", n if n > 5 => "medium", _ => "small" }`, "medium"},
+		{`match ([1, 2, 3]) { [] => 0, [first, ..rest] => first + len(rest) }`, 3},
+		{`match ([1, 2, 3]) { [a, b] => "two", [a, b, c] => a + b + c }`, 6},
+		{`match ([1, 2]) { [1, ..] => "starts with one", _ => "other" }`, "starts with one"},
+		{`match ([1]) { [a, ..rest] => rest }`, "[]"},
+		{`match ([[1, 2], 3]) { [[a, b], c] => a + b + c }`, 6},
+		{`match ({"type": "circle", "r": 5}) { {"type": "square", "side": s} => s, {"type": "circle", "r": r} => r * 2 }`, 10},
+		{`match ({"name": "Ann", "age": 30}) { {name, age: years} => "${name} ${years}" }`, "Ann 30"},
+		{`match ({"a": 1}) { {"b": b} => b, _ => "no b" }`, "no b"},
+		{`match ("x") { [a] => a, {a} => a, _ => "neither" }`, "neither"},
+		{`match (4) { n if n % 2 == 0 => { let half = n / 2; half } _ => n }`, 2},
+		{`let n = 1; match (5) { n => n }; n`, 1},
+		{`let f = fn(x) { match (x) { 0 => { sayonara "zero"; } _ => "other" }; "after" }; f(0)`, "zero"},
+		{`match (1) { 1 => { } }`, evaluator.NULL},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		switch expected := tt.expected.(type) {
+		case int:
+			testIntegerObject(t, evaluated, int64(expected))
+		case bool:
+			testBooleanObject(t, evaluated, expected)
+		case string:
+			if evaluated.Inspect() != expected {
+				t.Errorf("%q wrong result. expected=%s, got=%s", tt.input, expected, evaluated.Inspect())
+			}
+		default:
+			if evaluated != expected {
+				t.Errorf("%q wrong result. expected=%v, got=%v", tt.input, expected, evaluated)
+			}
+		}
+	}
+}
+
+func TestMatchExpressionErrors(t *testing.T) {
+	tests := []struct {
+		input           string
+		expectedMessage string
+	}{
+		{`match (3) { 1 => "one", 2 => "two" }`, "no match arm matches 3"},
+		{`match ([1, 2]) { [a] => a }`, "no match arm matches [1, 2]"},
+		{`match (1) { n if m => n }`, "identifier not found: m"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		errObj, ok := evaluated.(*object.Error)
+		if !ok {
+			t.Errorf("%q: no error object returned. got=%T(%+v)", tt.input, evaluated, evaluated)
+			continue
+		}
+		if errObj.Message != tt.expectedMessage {
+			t.Errorf("%q: wrong error message. expected=%q, got=%q", tt.input, tt.expectedMessage, errObj.Message)
+		}
+	}
+}
+
+func TestDestructuringLet(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"let [a, b] = [1, 2]; a * 10 + b", 12},
+		{"let [a, b, ..rest] = [1, 2, 3, 4]; rest", "[3, 4]"},
+		{"let [a, b, ..rest] = [1, 2]; rest", "[]"},
+		{"let [first, ..] = [7, 8, 9]; first", 7},
+		{"let [_, second] = [1, 2]; second", 2},
+		{`let {name, age: years} = {"name": "Ann", "age": 30}; "${name} ${years}"`, "Ann 30"},
+		{`let {"x": x, 1: one} = {"x": 5, 1: "one"}; "${x} ${one}"`, "5 one"},
+		{"let [x, [y, z], {k}] = [1, [2, 3], {\"k\": 4}]; x + y + z + k", 10},
+		{"let [a, b = 10] = [1]; a + b", 11},
+		{"let [a, b = 10] = [1, 2]; a + b", 3},
+		{"let [a, b = a * 2] = [4]; b", 8},
+		{`let {name = "anon"} = {}; name`, "anon"},
+		{`let {age: years = 18} = {"age": 30}; years`, 30},
+		{"let f = fn() { [1, 2] }; let [q, r] = f(); q + r", 3},
+		{"let a = 1; if (true) { let [a] = [2]; }; a", 1},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		switch expected := tt.expected.(type) {
+		case int:
+			testIntegerObject(t, evaluated, int64(expected))
+		case string:
+			if evaluated.Inspect() != expected {
+				t.Errorf("%q wrong result. expected=%s, got=%s", tt.input, expected, evaluated.Inspect())
+			}
+		}
+	}
+}
+
+func TestDestructuringErrors(t *testing.T) {
+	tests := []struct {
+		input           string
+		expectedMessage string
+	}{
+		{"let [a, b] = [1];", "cannot destructure array of length 1: expected 2 elements"},
+		{"let [a, b] = [1, 2, 3];", "cannot destructure array of length 3: expected 2 elements"},
+		{"let [a, b, ..rest] = [1];", "cannot destructure array of length 1: expected at least 2 elements"},
+		{"let [a, b, c = 1] = [];", "cannot destructure array of length 0: expected at least 2 elements"},
+		{`let {name} = {"age": 1};`, "cannot destructure hash: key not found: name"},
+		{"let [a] = 5;", "cannot destructure INTEGER as an array"},
+		{"let {a} = [1];", "cannot destructure ARRAY as a hash"},
+		{"let [a = b] = [];", "identifier not found: b"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		errObj, ok := evaluated.(*object.Error)
+		if !ok {
+			t.Errorf("%q: no error object returned. got=%T(%+v)", tt.input, evaluated, evaluated)
+			continue
+		}
+		if errObj.Message != tt.expectedMessage {
+			t.Errorf("%q: wrong error message. expected=%q, got=%q", tt.input, tt.expectedMessage, errObj.Message)
+		}
+	}
+}
+
+func TestDefaultAndNamedArguments(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected int64
+	}{
+		{"let f = fn(x, y = 10) { x + y }; f(1)", 11},
+		{"let f = fn(x, y = 10) { x + y }; f(1, 2)", 3},
+		{"let f = fn(x, y = x * 2) { y }; f(4)", 8},
+		{"let n = 0; let f = fn(x = n) { x }; let n = 5; f()", 5},
+		{"let count = 0; let next = fn() { count = count + 1; count }; let f = fn(x = next()) { x }; f(); f(); f(7); count", 2},
+		{"let f = fn(x, y) { x - y }; f(y: 1, x: 10)", 9},
+		{"let f = fn(x, y) { x - y }; f(10, y: 1)", 9},
+		{"let f = fn(a, b = 2, c = 3) { a * 100 + b * 10 + c }; f(1, c: 9)", 129},
+		{"let f = fn(a = 1, b) { a + b }; f(b: 5)", 6},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		testIntegerObject(t, evaluated, tt.expected)
+	}
+}
+
+func TestArgumentBindingErrors(t *testing.T) {
+	tests := []struct {
+		input           string
+		expectedMessage string
+	}{
+		{"let f = fn(a = 1, b) { b }; f(5)", "missing argument b to f(a = 1, b)"},
+		{"let f = fn(x) { x }; f(1, x: 2)", "duplicate argument x to f(x)"},
+		{"let f = fn(x) { x }; f(x: 1, x: 2)", "duplicate argument x to f(x)"},
+		{"let f = fn(x) { x }; f(y: 1)", "unknown argument y to f(x)"},
+		{"let f = fn(x = missing) { x }; f()", "identifier not found: missing"},
+		{"let f = fn(x) { x }; f(x: missing)", "identifier not found: missing"},
+		{"len(x: [1])", "builtin functions do not take named arguments, got x"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		errObj, ok := evaluated.(*object.Error)
+		if !ok {
+			t.Errorf("%q: no error object returned. got=%T(%+v)", tt.input, evaluated, evaluated)
+			continue
+		}
+		if errObj.Message != tt.expectedMessage {
+			t.Errorf("%q: wrong error message. expected=%q, got=%q", tt.input, tt.expectedMessage, errObj.Message)
+		}
+	}
+}
+
+func TestRestParametersAndSpread(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"let f = fn(first, ...rest) { rest }; f(1, 2, 3)", "[2, 3]"},
+		{"let f = fn(first, ...rest) { rest }; f(1)", "[]"},
+		{"let f = fn(...all) { len(all) }; f()", 0},
+		{"let f = fn(x, y = 2, ...rest) { [x, y, rest] }; f(1)", "[1, 2, []]"},
+		{"let f = fn(x, y = 2, ...rest) { [x, y, rest] }; f(1, 5, 6, 7)", "[1, 5, [6, 7]]"},
+		{"let add = fn(a, b) { a + b }; let args = [1, 2]; add(...args)", 3},
+		{"let add = fn(a, b, c) { a + b + c }; add(1, ...[2, 3])", 6},
+		{"let add = fn(a, b) { a + b }; add(...[1], b: 10)", 11},
+		{"let sum = fn(...xs) { let s = 0; for (x in xs) { s += x }; s }; let wrap = fn(...xs) { sum(...xs) }; wrap(1, 2, 3)", 6},
+		{"puts(...[])", evaluator.NULL},
+		{"[...[1, 2], ...[], 3, ...[4]]", "[1, 2, 3, 4]"},
+		{"[0, ...1..3]", "[0, 1, 2, 3]"},
+		{"let a = [1]; let b = [...a]; push(b, 2); a", "[1]"},
+		{`let d = {"a": 1, "b": 2}; let h = {...d, "b": 3}; [h["a"], h["b"]]`, "[1, 3]"},
+		{`let h = {"b": 3, ...{"b": 2}}; h["b"]`, 2},
+		{`let h = {...{"a": 1}, ...{"a": 5, "c": 6}}; h["a"] + h["c"]`, 11},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		switch expected := tt.expected.(type) {
+		case int:
+			testIntegerObject(t, evaluated, int64(expected))
+		case string:
+			if evaluated.Inspect() != expected {
+				t.Errorf("%q wrong result. expected=%s, got=%s", tt.input, expected, evaluated.Inspect())
+			}
+		default:
+			if evaluated != expected {
+				t.Errorf("%q wrong result. expected=%v, got=%v", tt.input, expected, evaluated)
+			}
+		}
+	}
+}
+
+func TestRestParameterAndSpreadErrors(t *testing.T) {
+	tests := []struct {
+		input           string
+		expectedMessage string
+	}{
+		{"[...5]", "cannot spread INTEGER, expected ARRAY or RANGE"},
+		{"[...0..<9223372036854775807]", "range too large to expand: 0..<9223372036854775807 has 9223372036854775807 values, the limit is 4194304"},
+		{"[...0..100000000000]", "range too large to expand: 0..100000000000 has 100000000001 values, the limit is 4194304"},
+		{"len(...0..100000000000)", "range too large to expand: 0..100000000000 has 100000000001 values, the limit is 4194304"},
+		{"len(...missing)", "identifier not found: missing"},
+		{`{...[1]}`, "cannot spread ARRAY into a hash, expected HASH"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		errObj, ok := evaluated.(*object.Error)
+		if !ok {
+			t.Errorf("%q: no error object returned. got=%T(%+v)", tt.input, evaluated, evaluated)
+			continue
+		}
+		if errObj.Message != tt.expectedMessage {
+			t.Errorf("%q: wrong error message. expected=%q, got=%q", tt.input, tt.expectedMessage, errObj.Message)
+		}
+	}
+}
+
+func TestArityErrors(t *testing.T) {
+	tests := []struct {
+		input           string
+		expectedMessage string
+	}{
+		{"let add = fn(a, b) { a + b }; add(1)", "wrong number of arguments to add(a, b): expected 2, got 1"},
+		{"let add = fn(a, b) { a + b }; add(1, 2, 3)", "wrong number of arguments to add(a, b): expected 2, got 3"},
+		{"fn(x) { x }()", "wrong number of arguments to fn(x): expected 1, got 0"},
+		{"let f = fn(x, y = 10) { x }; f()", "wrong number of arguments to f(x, y = 10): expected 1 to 2, got 0"},
+		{"let f = fn(x, y = 10) { x }; f(1, 2, 3)", "wrong number of arguments to f(x, y = 10): expected 1 to 2, got 3"},
+		{"let f = fn(x, ...rest) { x }; f()", "wrong number of arguments to f(x, ...rest): expected at least 1, got 0"},
+		{"let f = fn(x, y) { x }; f(y: 1)", "wrong number of arguments to f(x, y): expected 2, got 1"},
+		{"let f = fn(x, y) { x }; f(...[1, 2, 3])", "wrong number of arguments to f(x, y): expected 2, got 3"},
+		{"len()", "wrong number of arguments to len(value): expected 1, got 0"},
+		{"push([1])", "wrong number of arguments to push(array, value): expected 2, got 1"},
+		{`charAt("a", 0, 1)`, "wrong number of arguments to charAt(string, index): expected 2, got 3"},
+		{"first([1], [2])", "wrong number of arguments to first(array): expected 1, got 2"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		errObj, ok := evaluated.(*object.Error)
+		if !ok {
+			t.Errorf("%q: no error object returned. got=%T(%+v)", tt.input, evaluated, evaluated)
+			continue
+		}
+		if errObj.Message != tt.expectedMessage {
+			t.Errorf("%q: wrong error message. expected=%q, got=%q", tt.input, tt.expectedMessage, errObj.Message)
+		}
+	}
+}
+
+func TestArrowFunctions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected int64
+	}{
+		{"let double = x => x * 2; double(4)", 8},
+		{"let add = (a, b) => a + b; add(2, 3)", 5},
+		{"let add = (a, b = 10) => a + b; add(2)", 12},
+		{"let f = () => 42; f()", 42},
+		{"let f = (x) => { let y = x + 1; y * y }; f(2)", 9},
+		{"let f = (x) => { if (x > 0) { sayonara 1; }; -1 }; f(-5)", -1},
+		{"let apply = fn(f, v) { f(v) }; apply(n => n + 1, 5)", 6},
+		{"let curry = a => b => a - b; curry(10)(3)", 7},
+		{"let sum = (...xs) => { let s = 0; for (x in xs) { s += x }; s }; sum(1, 2, 3)", 6},
+		{"let n = 10; let addN = x => x + n; addN(1)", 11},
+		{"let ok = true; match (5) { n if ok => n * 2, _ => 0 }", 10},
+		{"(x => x * 3)(3)", 9},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		testIntegerObject(t, evaluated, tt.expected)
+	}
+}
+
+func TestArrowFunctionArityErrors(t *testing.T) {
+	tests := []struct {
+		input           string
+		expectedMessage string
+	}{
+		{"let add = (a, b) => a + b; add(1)", "wrong number of arguments to add(a, b): expected 2, got 1"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		errObj, ok := evaluated.(*object.Error)
+		if !ok {
+			t.Errorf("%q: no error object returned. got=%T(%+v)", tt.input, evaluated, evaluated)
+			continue
+		}
+		if errObj.Message != tt.expectedMessage {
+			t.Errorf("%q: wrong error message. expected=%q, got=%q", tt.input, tt.expectedMessage, errObj.Message)
+		}
+	}
+}
+
+func TestWhileExpressionFunction(t *testing.T) {
+	test := []struct {
+		input    string
+		expected interface{}
+	}{
+		{
+			"let i = 0; while (i < 10) { let i += 1; }",
+			evaluator.NULL,
+		},
+	}
+
+	for _, tt := range test {
+		evaluated := testEval(tt.input)
+		if evaluated != tt.expected {
+			t.Errorf("expected %v, got %v", tt.expected, evaluated)
+		}
+	}
+}
+
+func TestBlockScoping(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected int64
+	}{
+		{"let x = 1; if (true) { let x = 2; }; x", 1},
+		{"let x = 1; if (true) { x = 2; }; x", 2},
+		{"let i = 0; while (i < 3) { let i += 1; }; i", 3},
+		{"let i = 10; for (let i = 0; i < 3; let i++) { i }; i", 10},
+		{"let total = 0; for (let i = 0; i < 4; let i++) { total += i }; total", 6},
+		{"let f = fn() { let x = 1; if (true) { let x = 2; }; x }; f()", 1},
+		{"let fns = []; for (let i = 0; i < 3; let i++) { fns = push(fns, fn() { i }) }; fns[0]() + fns[1]() * 10 + fns[2]() * 100", 210},
+		{"let fns = []; for (let i = 0; i < 3; let i++) { let j = i * 2; fns = push(fns, fn() { j }) }; fns[2]()", 4},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		testIntegerObject(t, evaluated, tt.expected)
+	}
+}
+
+func TestBlockScopingErrors(t *testing.T) {
+	tests := []struct {
+		input           string
+		expectedMessage string
+	}{
+		{"if (true) { let y = 2; }; y", "identifier not found: y"},
+		{"let i = 0; while (i < 3) { let j = i; let i += 1; }; j", "identifier not found: j"},
+		{"for (let i = 0; i < 3; let i++) { i }; i", "identifier not found: i"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		errObj, ok := evaluated.(*object.Error)
+		if !ok {
+			t.Errorf("%q: no error object returned. got=%T(%+v)", tt.input, evaluated, evaluated)
+			continue
+		}
+		if errObj.Message != tt.expectedMessage {
+			t.Errorf("%q: wrong error message. expected=%q, got=%q", tt.input, tt.expectedMessage, errObj.Message)
+		}
+	}
+}
+
+func TestEvalFloatExpression(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected float64
+	}{
+		{"3.14", 3.14},
+		{"-2.5", -2.5},
+		{"1.5 + 1.5", 3.0},
+		{"1 + 0.5", 1.5},
+		{"0.5 * 4", 2.0},
+		{"7 / 2.0", 3.5},
+		{"10.0 - 2 * 3", 4.0},
+		{"let total = 7; let count = 2; total / count", 3.5},
+	}
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		testFloatObject(t, evaluated, tt.expected)
+	}
+}
+
+func testFloatObject(t *testing.T, obj object.Object, expected float64) bool {
+	result, ok := obj.(*object.Float)
+	if !ok {
+		t.Errorf("object is not Float. got=%T (%+v)", obj, obj)
+		return false
+	}
+	if result.Value != expected {
+		t.Errorf("object has wrong value. got=%g, want=%g",
+			result.Value, expected)
+		return false
+	}
+	return true
+}
+
+func TestIntegerDivision(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"7 / 2", 3.5},
+		{"-7 / 2", -3.5},
+		{"6 / 3", 2},
+		{"7 ~/ 2", 3},
+		{"-7 ~/ 2", -3},
+		{"7.5 ~/ 2", 3},
+		{"-7.5 ~/ 2", -3},
+		{"1 / 0", "division by zero"},
+		{"1.5 / 0", "division by zero"},
+		{"1 ~/ 0.0", "division by zero"},
+	}
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		switch expected := tt.expected.(type) {
+		case int:
+			testIntegerObject(t, evaluated, int64(expected))
+		case float64:
+			testFloatObject(t, evaluated, expected)
+		case string:
+			errObj, ok := evaluated.(*object.Error)
+			if !ok {
+				t.Errorf("object is not Error. got=%T (%+v)", evaluated, evaluated)
+				continue
+			}
+			if errObj.Message != expected {
+				t.Errorf("wrong error message. expected=%q, got=%q", expected, errObj.Message)
+			}
+		}
+	}
+}
+
+func TestMixedNumberComparison(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected bool
+	}{
+		{"1 == 1.0", true},
+		{"1.0 != 1", false},
+		{"0.5 < 1", true},
+		{"2 > 2.5", false},
+		{"1.5 == 1.5", true},
+	}
+	for _, tt := range tests {
+		testBooleanObject(t, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestFloatInspect(t *testing.T) {
+	tests := []struct {
+		value    float64
+		expected string
+	}{
+		{3.0, "3.0"},
+		{3.14, "3.14"},
+		{-0.5, "-0.5"},
+		{1e21, "1e+21"},
+	}
+	for _, tt := range tests {
+		f := &object.Float{Value: tt.value}
+		if f.Inspect() != tt.expected {
+			t.Errorf("Inspect() wrong. expected=%q, got=%q", tt.expected, f.Inspect())
+		}
+	}
+}
+
+func TestArithmeticAndBitwiseOperators(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"7 % 3", 7 % 3},
+		{"-7 % 3", -7 % 3},
+		{"7.5 % 2", 1.5},
+		{"6 & 3", 6 & 3},
+		{"6 | 3", 6 | 3},
+		{"6 ^ 3", 6 ^ 3},
+		{"~5", ^5},
+		{"1 << 4", 1 << 4},
+		{"-16 >> 2", -16 >> 2},
+		{"-1 >> 100", -1},
+		{"2 ** 10", 1024},
+		{"2 ** 3 ** 2", 512},
+		{"-2 ** 2", -4},
+		{"2 ** -1", 0.5},
+		{"4.0 ** 0.5", 2.0},
+		{"1 | 2 ^ 3 & 4", 1 | (2 ^ (3 & 4))},
+		{"let x = 10; let x %= 4; x", 2},
+		{"let x = 12; let x &= 10; x", 12 & 10},
+		{"let x = 12; let x ^= 10; x", 12 ^ 10},
+		{"let x = 12; let x |= 3; x", 12 | 3},
+		{"1 << 64", "18446744073709551616"},
+		{"2 ** 100", "1267650600228229401496703205376"},
+		{"2 ** 100 % 7", 2},
+		{"(1 << 70) >> 69", 2},
+		{"~(1 << 70)", "-1180591620717411303425"},
+		{"1 ** 1000000000000", 1},
+		{"-1 ** 1000000000001", -1},
+		{"0 ** 1000000000000", 0},
+		{"(2 ** 100000) >> 99999", 2},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		switch expected := tt.expected.(type) {
+		case int:
+			testIntegerObject(t, evaluated, int64(expected))
+		case float64:
+			testFloatObject(t, evaluated, expected)
+		case string:
+			if evaluated.Inspect() != expected {
+				t.Errorf("%q wrong result. expected=%s, got=%s", tt.input, expected, evaluated.Inspect())
+			}
+		}
+	}
+}
+
+func TestArithmeticAndBitwiseOperatorErrors(t *testing.T) {
+	tests := []struct {
+		input           string
+		expectedMessage string
+	}{
+		{"5 % 0", "division by zero"},
+		{"1 << -1", "negative shift count: -1"},
+		{"1 << 2000000", "shift count too large: 2000000"},
+		{"1 >> 99999999999999999999", "shift count too large: 99999999999999999999"},
+		{"(1 << 1000000) << 100000", "integer too large: result of << would exceed 1048576 bits"},
+		{"3 ** 1000000000", "integer too large: result of ** would exceed 1048576 bits"},
+		{"(2 ** 64) ** 100000", "integer too large: result of ** would exceed 1048576 bits"},
+		{"1.5 & 1", "unknown operator: FLOAT & INTEGER"},
+		{`~"a"`, "unknown operator: ~STRING"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		errObj, ok := evaluated.(*object.Error)
+		if !ok {
+			t.Errorf("%q: no error object returned. got=%T(%+v)", tt.input, evaluated, evaluated)
+			continue
+		}
+		if errObj.Message != tt.expectedMessage {
+			t.Errorf("%q: wrong error message. expected=%q, got=%q", tt.input, tt.expectedMessage, errObj.Message)
+		}
+	}
+}
+
+func TestBigIntegerPromotion(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"9223372036854775807 + 1", "9223372036854775808"},
+		{"-9223372036854775807 - 2", "-9223372036854775809"},
+		{"4294967296 * 4294967296", "18446744073709551616"},
+		{"let min = -9223372036854775807 - 1; -min", "9223372036854775808"},
+		{"123456789012345678901234567890", "123456789012345678901234567890"},
+		{"123456789012345678901234567890 / 10", "12345678901234567890123456789"},
+		{"123456789012345678901234567890 * 0 + 5 - 5", "0"},
+		{
+			`let factorial = fn(n) { if (n < 2) { sayonara 1; } n * factorial(n - 1) };
+			factorial(25)`,
+			"15511210043330985984000000",
+		},
+	}
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		if evaluated == nil {
+			t.Errorf("%q evaluated to nil", tt.input)
+			continue
+		}
+		if evaluated.Inspect() != tt.expected {
+			t.Errorf("%q wrong result. expected=%s, got=%s (%T)", tt.input, tt.expected, evaluated.Inspect(), evaluated)
+		}
+	}
+}
+
+func TestBigIntegerDemotion(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected int64
+	}{
+		{"9223372036854775808 - 1", 9223372036854775807},
+		{"18446744073709551616 / 4294967296", 4294967296},
+		{"let big = 9223372036854775807 + 10; big - 10 - 7", 9223372036854775800},
+	}
+	for _, tt := range tests {
+		testIntegerObject(t, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestBigIntegerOperators(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"9223372036854775808 > 1", true},
+		{"9223372036854775808 == 9223372036854775807 + 1", true},
+		{"9223372036854775808 != 1", true},
+		{"9223372036854775808 < 1.5", false},
+		{"9223372036854775808 * 0.5", 4611686018427387904.0},
+		{"9223372036854775809 / 2", 4611686018427387904.5},
+		{`{9223372036854775808: "big"}[9223372036854775807 + 1]`, "big"},
+		{"0xFFFFFFFFFFFFFFFFFF", "4722366482869645213695"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		switch expected := tt.expected.(type) {
+		case bool:
+			testBooleanObject(t, evaluated, expected)
+		case float64:
+			testFloatObject(t, evaluated, expected)
+		case string:
+			if evaluated.Inspect() != expected {
+				t.Errorf("%q wrong result. expected=%s, got=%s", tt.input, expected, evaluated.Inspect())
+			}
+		}
+	}
+}
+
+func TestBigIntegerOperatorErrors(t *testing.T) {
+	tests := []struct {
+		input           string
+		expectedMessage string
+	}{
+		{"1 / (9223372036854775808 - 9223372036854775808)", "division by zero"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		errObj, ok := evaluated.(*object.Error)
+		if !ok {
+			t.Errorf("%q: no error object returned. got=%T(%+v)", tt.input, evaluated, evaluated)
+			continue
+		}
+		if errObj.Message != tt.expectedMessage {
+			t.Errorf("%q: wrong error message. expected=%q, got=%q", tt.input, tt.expectedMessage, errObj.Message)
 		}
 	}
 }
@@ -17,7 +17,7 @@ func TestNextToken(t *testing.T) {
 		};
 
 		let result = add(five, ten);
-		!-/*5;
+		!-/ *5;
 		5 < 10 > 5;
 
 		if (5 < 10) {
@@ -29,6 +29,7 @@ func TestNextToken(t *testing.T) {
 		10 != 9;
 		let foo = "foobar"
 		"foo bar"
+		3.14 ~/ 2;
 		[1, 2];
 		{"foo": "bar"}
 		yamete;
@@ -126,6 +127,10 @@ func TestNextToken(t *testing.T) {
 		{token.ASSIGN, "="},
 		{token.STRING, "foobar"},
 		{token.STRING, "foo bar"},
+		{token.FLOAT, "3.14"},
+		{token.INTDIVIDE, "~/"},
+		{token.INT, "2"},
+		{token.SEMICOLON, ";"},
 		{token.LBRACKET, "["},
 		{token.INT, "1"},
 		{token.COMMA, ","},
@@ -200,3 +205,350 @@ func TestNextToken(t *testing.T) {
 		}
 	}
 }
+
+func TestNextTokenPositions(t *testing.T) {
+	input := "let x = 5;\n  add(x, 10)"
+
+	tests := []struct {
+		expectedType   token.TokenType
+		expectedPos    token.Position
+		expectedEndCol int
+	}{
+		{token.LET, token.Position{Offset: 0, Line: 1, Column: 1}, 4},
+		{token.IDENTFIER, token.Position{Offset: 4, Line: 1, Column: 5}, 6},
+		{token.ASSIGN, token.Position{Offset: 6, Line: 1, Column: 7}, 8},
+		{token.INT, token.Position{Offset: 8, Line: 1, Column: 9}, 10},
+		{token.SEMICOLON, token.Position{Offset: 9, Line: 1, Column: 10}, 11},
+		{token.IDENTFIER, token.Position{Offset: 13, Line: 2, Column: 3}, 6},
+		{token.LPAREN, token.Position{Offset: 16, Line: 2, Column: 6}, 7},
+		{token.IDENTFIER, token.Position{Offset: 17, Line: 2, Column: 7}, 8},
+		{token.COMMA, token.Position{Offset: 18, Line: 2, Column: 8}, 9},
+		{token.INT, token.Position{Offset: 20, Line: 2, Column: 10}, 12},
+		{token.RPAREN, token.Position{Offset: 22, Line: 2, Column: 12}, 13},
+		{token.EOF, token.Position{Offset: 23, Line: 2, Column: 13}, 13},
+	}
+
+	l := lexer.New(input)
+
+	for i, tt := range tests {
+		tok := l.NextToken()
+
+		if tok.Type != tt.expectedType {
+			t.Fatalf("tests[%d] - tokentype wrong. expected=%q, got=%q", i, tt.expectedType, tok.Type)
+		}
+
+		if tok.Pos != tt.expectedPos {
+			t.Fatalf("tests[%d] - position wrong. expected=%+v, got=%+v", i, tt.expectedPos, tok.Pos)
+		}
+
+		if tok.End.Column != tt.expectedEndCol {
+			t.Fatalf("tests[%d] - end column wrong. expected=%d, got=%d", i, tt.expectedEndCol, tok.End.Column)
+		}
+	}
+}
+
+func TestComments(t *testing.T) {
+	input := `// leading comment
+let x = 5; // trailing comment
+/* block /* nested */ still comment */ x / 2;
+// last comment`
+
+	tests := []struct {
+		expectedType    token.TokenType
+		expectedLiteral string
+		expectedLeading []string
+	}{
+		{token.LET, "let", []string{"// leading comment"}},
+		{token.IDENTFIER, "x", nil},
+		{token.ASSIGN, "=", nil},
+		{token.INT, "5", nil},
+		{token.SEMICOLON, ";", nil},
+		{token.IDENTFIER, "x", []string{"// trailing comment", "/* block /* nested */ still comment */"}},
+		{token.SLASH, "/", nil},
+		{token.INT, "2", nil},
+		{token.SEMICOLON, ";", nil},
+		{token.EOF, "", []string{"// last comment"}},
+	}
+
+	l := lexer.New(input)
+
+	for i, tt := range tests {
+		tok := l.NextToken()
+
+		if tok.Type != tt.expectedType {
+			t.Fatalf("tests[%d] - tokentype wrong. expected=%q, got=%q", i, tt.expectedType, tok.Type)
+		}
+
+		if tok.Literal != tt.expectedLiteral {
+			t.Fatalf("tests[%d] - literal wrong. expected=%q, got=%q", i, tt.expectedLiteral, tok.Literal)
+		}
+
+		if len(tok.Leading) != len(tt.expectedLeading) {
+			t.Fatalf("tests[%d] - wrong number of comments. expected=%d, got=%d", i, len(tt.expectedLeading), len(tok.Leading))
+		}
+
+		for j, comment := range tt.expectedLeading {
+			if tok.Leading[j].Literal != comment {
+				t.Errorf("tests[%d] - comment %d wrong. expected=%q, got=%q", i, j, comment, tok.Leading[j].Literal)
+			}
+		}
+	}
+
+	if len(l.Errors()) != 0 {
+		t.Errorf("lexer has unexpected errors: %v", l.Errors())
+	}
+}
+
+func TestUnterminatedBlockComment(t *testing.T) {
+	l := lexer.New("let x = 1; /* never /* closed */")
+
+	for tok := l.NextToken(); tok.Type != token.EOF; tok = l.NextToken() {
+	}
+
+	errors := l.Errors()
+	if len(errors) != 1 {
+		t.Fatalf("expected 1 lexer error, got=%d", len(errors))
+	}
+
+	if errors[0].Message != "unterminated block comment" {
+		t.Errorf("wrong error message. got=%q", errors[0].Message)
+	}
+
+	if errors[0].Span.Start.Column != 12 {
+		t.Errorf("wrong error column. expected=12, got=%d", errors[0].Span.Start.Column)
+	}
+}
+
+func TestMalformedNumberLiterals(t *testing.T) {
+	tests := []struct {
+		input   string
+		message string
+	}{
+		{"0xZZ", "invalid digit 'Z' in hexadecimal literal"},
+		{"0b102", "invalid digit '2' in binary literal"},
+		{"0o8", "invalid digit '8' in octal literal"},
+		{"0x", "hexadecimal literal has no digits"},
+		{"1e", "exponent has no digits"},
+		{"12ab", "invalid character 'a' in number literal"},
+		{"1__0", "'_' must separate successive digits"},
+		{"100_", "'_' must separate successive digits"},
+		{"010", "leading zero in decimal literal 010; use 0o10 for octal"},
+		{"0_7", "leading zero in decimal literal 0_7; use 0o7 for octal"},
+		{"007", "leading zero in decimal literal 007; use 0o7 for octal"},
+		{"00", "leading zero in decimal literal 00; use 0o0 for octal"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+
+		tok := l.NextToken()
+		if tok.Type != token.ILLEGAL {
+			t.Errorf("%q: expected ILLEGAL token, got=%q", tt.input, tok.Type)
+		}
+
+		errors := l.Errors()
+		if len(errors) != 1 {
+			t.Errorf("%q: expected 1 lexer error, got=%d", tt.input, len(errors))
+			continue
+		}
+
+		if errors[0].Message != tt.message {
+			t.Errorf("%q: wrong error message. expected=%q, got=%q", tt.input, tt.message, errors[0].Message)
+		}
+	}
+}
+
+func TestInvalidStringEscapes(t *testing.T) {
+	tests := []struct {
+		input   string
+		message string
+		column  int
+	}{
+		{`"bad \q escape"`, `unknown escape sequence '\q'`, 6},
+		{`"\x4"`, `\x escape must be followed by two hexadecimal digits`, 2},
+		{`"ok \u0041"`, `\u escape must have the form \u{XXXX}`, 5},
+		{`"\u{41"`, `\u escape must have the form \u{XXXX}`, 2},
+		{`"\u{D800}"`, "invalid Unicode code point U+D800", 2},
+		{`"never closed`, "unterminated string literal", 1},
+		{"x = `never closed", "unterminated raw string literal", 5},
+		{`"""never "" closed`, "unterminated triple-quoted string literal", 1},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+
+		for tok := l.NextToken(); tok.Type != token.EOF; tok = l.NextToken() {
+		}
+
+		errors := l.Errors()
+		if len(errors) != 1 {
+			t.Errorf("%s: expected 1 lexer error, got=%d", tt.input, len(errors))
+			continue
+		}
+
+		if errors[0].Message != tt.message {
+			t.Errorf("%s: wrong error message. expected=%q, got=%q", tt.input, tt.message, errors[0].Message)
+		}
+
+		if errors[0].Span.Start.Column != tt.column {
+			t.Errorf("%s: wrong error column. expected=%d, got=%d", tt.input, tt.column, errors[0].Span.Start.Column)
+		}
+	}
+}
+
+func TestStringInterpolationTokens(t *testing.T) {
+	input := `"Hello ${name}, ${ {"a": "${x}"}["a"] } items" "\${plain} $5"`
+
+	tests := []struct {
+		expectedType    token.TokenType
+		expectedLiteral string
+	}{
+		{token.STRINGHEAD, "Hello "},
+		{token.IDENTFIER, "name"},
+		{token.STRINGMID, ", "},
+		{token.LBRACE, "{"},
+		{token.STRING, "a"},
+		{token.COLON, ":"},
+		{token.STRINGHEAD, ""},
+		{token.IDENTFIER, "x"},
+		{token.STRINGTAIL, ""},
+		{token.RBRACE, "}"},
+		{token.LBRACKET, "["},
+		{token.STRING, "a"},
+		{token.RBRACKET, "]"},
+		{token.STRINGTAIL, " items"},
+		{token.STRING, "${plain} $5"},
+		{token.EOF, ""},
+	}
+
+	l := lexer.New(input)
+
+	for i, tt := range tests {
+		tok := l.NextToken()
+
+		if tok.Type != tt.expectedType {
+			t.Fatalf("tests[%d] - tokentype wrong. expected=%q, got=%q", i, tt.expectedType, tok.Type)
+		}
+
+		if tok.Literal != tt.expectedLiteral {
+			t.Fatalf("tests[%d] - literal wrong. expected=%q, got=%q", i, tt.expectedLiteral, tok.Literal)
+		}
+	}
+}
+
+func TestUnicodeInput(t *testing.T) {
+	input := "let café = \"😀\"; 名前"
+
+	tests := []struct {
+		expectedType    token.TokenType
+		expectedLiteral string
+		expectedPos     token.Position
+	}{
+		{token.LET, "let", token.Position{Offset: 0, Line: 1, Column: 1}},
+		{token.IDENTFIER, "café", token.Position{Offset: 4, Line: 1, Column: 5}},
+		{token.ASSIGN, "=", token.Position{Offset: 10, Line: 1, Column: 10}},
+		{token.STRING, "😀", token.Position{Offset: 12, Line: 1, Column: 12}},
+		{token.SEMICOLON, ";", token.Position{Offset: 18, Line: 1, Column: 15}},
+		{token.IDENTFIER, "名前", token.Position{Offset: 20, Line: 1, Column: 17}},
+		{token.EOF, "", token.Position{Offset: 26, Line: 1, Column: 19}},
+	}
+
+	l := lexer.New(input)
+
+	for i, tt := range tests {
+		tok := l.NextToken()
+
+		if tok.Type != tt.expectedType {
+			t.Fatalf("tests[%d] - tokentype wrong. expected=%q, got=%q", i, tt.expectedType, tok.Type)
+		}
+
+		if tok.Literal != tt.expectedLiteral {
+			t.Fatalf("tests[%d] - literal wrong. expected=%q, got=%q", i, tt.expectedLiteral, tok.Literal)
+		}
+
+		if tok.Pos != tt.expectedPos {
+			t.Fatalf("tests[%d] - position wrong. expected=%+v, got=%+v", i, tt.expectedPos, tok.Pos)
+		}
+	}
+}
+
+func TestRangeTokens(t *testing.T) {
+	input := "1..10 0..<n 1.5..2 ...xs"
+
+	tests := []struct {
+		expectedType    token.TokenType
+		expectedLiteral string
+	}{
+		{token.INT, "1"},
+		{token.DOTDOT, ".."},
+		{token.INT, "10"},
+		{token.INT, "0"},
+		{token.DOTDOTLESS, "..<"},
+		{token.IDENTFIER, "n"},
+		{token.FLOAT, "1.5"},
+		{token.DOTDOT, ".."},
+		{token.INT, "2"},
+		{token.ELLIPSIS, "..."},
+		{token.IDENTFIER, "xs"},
+		{token.EOF, ""},
+	}
+
+	l := lexer.New(input)
+
+	for i, tt := range tests {
+		tok := l.NextToken()
+
+		if tok.Type != tt.expectedType {
+			t.Fatalf("tests[%d] - tokentype wrong. expected=%q, got=%q", i, tt.expectedType, tok.Type)
+		}
+
+		if tok.Literal != tt.expectedLiteral {
+			t.Fatalf("tests[%d] - literal wrong. expected=%q, got=%q", i, tt.expectedLiteral, tok.Literal)
+		}
+	}
+}
+
+func TestMatchTokens(t *testing.T) {
+	input := `match (x) { 1 | 2 => a, [h, ..t] => h }`
+
+	tests := []struct {
+		expectedType    token.TokenType
+		expectedLiteral string
+	}{
+		{token.MATCH, "match"},
+		{token.LPAREN, "("},
+		{token.IDENTFIER, "x"},
+		{token.RPAREN, ")"},
+		{token.LBRACE, "{"},
+		{token.INT, "1"},
+		{token.BITWISEOR, "|"},
+		{token.INT, "2"},
+		{token.ARROW, "=>"},
+		{token.IDENTFIER, "a"},
+		{token.COMMA, ","},
+		{token.LBRACKET, "["},
+		{token.IDENTFIER, "h"},
+		{token.COMMA, ","},
+		{token.DOTDOT, ".."},
+		{token.IDENTFIER, "t"},
+		{token.RBRACKET, "]"},
+		{token.ARROW, "=>"},
+		{token.IDENTFIER, "h"},
+		{token.RBRACE, "}"},
+		{token.EOF, ""},
+	}
+
+	l := lexer.New(input)
+
+	for i, tt := range tests {
+		tok := l.NextToken()
+
+		if tok.Type != tt.expectedType {
+			t.Fatalf("tests[%d] - tokentype wrong. expected=%q, got=%q", i, tt.expectedType, tok.Type)
+		}
+
+		if tok.Literal != tt.expectedLiteral {
+			t.Fatalf("tests[%d] - literal wrong. expected=%q, got=%q", i, tt.expectedLiteral, tok.Literal)
+		}
+	}
+}
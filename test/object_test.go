@@ -1,6 +1,8 @@
 package test
 
 import (
+	"math"
+	"math/big"
 	"testing"
 
 	"github.com/anilang-official/AniLang/object"
@@ -21,3 +23,77 @@ func TestStringHashKey(t *testing.T) {
 		t.Errorf("strings with different content have same hash keys")
 	}
 }
+
+func TestNumberHashKeys(t *testing.T) {
+	big64 := new(big.Int).Lsh(big.NewInt(1), 64)
+
+	same := [][2]object.Hashable{
+		{&object.Integer{Value: 1}, &object.Float{Value: 1.0}},
+		{&object.Integer{Value: 0}, &object.Float{Value: math.Copysign(0, -1)}},
+		{&object.Integer{Value: -7}, &object.Float{Value: -7.0}},
+		{&object.BigInteger{Value: big64}, &object.Float{Value: 18446744073709551616.0}},
+	}
+	for _, pair := range same {
+		if pair[0].HashKey() != pair[1].HashKey() {
+			t.Errorf("equal numbers have different hash keys: %s and %s",
+				pair[0].(object.Object).Inspect(), pair[1].(object.Object).Inspect())
+		}
+	}
+
+	different := [][2]object.Hashable{
+		{&object.Integer{Value: 1}, &object.Float{Value: 1.5}},
+		{&object.Float{Value: 0.5}, &object.Float{Value: 1.5}},
+		{&object.Integer{Value: 1}, &object.Boolean{Value: true}},
+	}
+	for _, pair := range different {
+		if pair[0].HashKey() == pair[1].HashKey() {
+			t.Errorf("different values have the same hash key: %s and %s",
+				pair[0].(object.Object).Inspect(), pair[1].(object.Object).Inspect())
+		}
+	}
+}
+
+func TestRangeAtInt64Limits(t *testing.T) {
+	const max, min = int64(9223372036854775807), int64(-9223372036854775808)
+
+	tests := []struct {
+		rng      *object.Range
+		length   int64
+		tooLong  bool
+		contains []int64
+		excludes []int64
+	}{
+		{&object.Range{Start: 0, End: max, Step: 1}, max, false, []int64{0, 5, max - 1}, []int64{max, -1}},
+		{&object.Range{Start: 0, End: max, Step: 1, Inclusive: true}, 0, true, nil, nil},
+		{&object.Range{Start: min, End: max, Step: 1, Inclusive: true}, 0, true, nil, nil},
+		{&object.Range{Start: min, End: max, Step: 2, Inclusive: true}, 0, true, nil, nil},
+		{&object.Range{Start: min, End: max, Step: 4}, 1 << 62, false, []int64{min, min + 4, max - 3}, []int64{min + 1, max}},
+		{&object.Range{Start: max, End: min, Step: min, Inclusive: true}, 2, false, []int64{max, -1}, []int64{min, 0}},
+		{&object.Range{Start: max, End: 0, Step: -1}, max, false, []int64{max, 1}, []int64{0}},
+	}
+
+	for i, tt := range tests {
+		if tt.rng.TooLong() != tt.tooLong {
+			t.Errorf("tests[%d] - TooLong wrong. expected=%t, got=%t", i, tt.tooLong, tt.rng.TooLong())
+		}
+		if tt.tooLong {
+			continue
+		}
+		if tt.rng.Len() != tt.length {
+			t.Errorf("tests[%d] - Len wrong. expected=%d, got=%d", i, tt.length, tt.rng.Len())
+		}
+		if last := tt.rng.At(tt.rng.Len() - 1); !tt.rng.Contains(last) {
+			t.Errorf("tests[%d] - last value %d not contained", i, last)
+		}
+		for _, n := range tt.contains {
+			if !tt.rng.Contains(n) {
+				t.Errorf("tests[%d] - expected %d to be in %s", i, n, tt.rng.Inspect())
+			}
+		}
+		for _, n := range tt.excludes {
+			if tt.rng.Contains(n) {
+				t.Errorf("tests[%d] - expected %d not to be in %s", i, n, tt.rng.Inspect())
+			}
+		}
+	}
+}
@@ -124,6 +124,79 @@ func TestIntegerLiteralExpression(t *testing.T) {
 	}
 }
 
+func TestNumberLiteralForms(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"0xFF", 255},
+		{"0XfF", 255},
+		{"0b1010", 10},
+		{"0o17", 15},
+		{"1_000_000", 1000000},
+		{"0x_ff", 255},
+		{"0b1010_1010", 170},
+		{"0", 0},
+		{"0.5", 0.5},
+		{"010.5", 10.5},
+		{"0e3", 0.0},
+		{"1e3", 1000.0},
+		{"2.5e-1", 0.25},
+		{"1_0.2_5", 10.25},
+		{"6E+2", 600.0},
+	}
+
+	for _, tt := range tests {
+		program := createParseProgram(tt.input, t)
+		stmt := program.Statements[0].(*ast.ExpressionStatement)
+
+		switch expected := tt.expected.(type) {
+		case int:
+			literal, ok := stmt.Expression.(*ast.IntegerLiteral)
+			if !ok {
+				t.Errorf("%q: exp not %T. got=%T", tt.input, &ast.IntegerLiteral{}, stmt.Expression)
+				continue
+			}
+			if literal.Value != int64(expected) {
+				t.Errorf("%q: literal.Value not %d. got=%d", tt.input, expected, literal.Value)
+			}
+		case float64:
+			literal, ok := stmt.Expression.(*ast.FloatLiteral)
+			if !ok {
+				t.Errorf("%q: exp not %T. got=%T", tt.input, &ast.FloatLiteral{}, stmt.Expression)
+				continue
+			}
+			if literal.Value != expected {
+				t.Errorf("%q: literal.Value not %g. got=%g", tt.input, expected, literal.Value)
+			}
+		}
+	}
+}
+
+func TestFloatLiteralExpression(t *testing.T) {
+	input := "3.25;"
+
+	program := createParseProgram(input, t)
+
+	stmt, ok := program.Statements[0].(*ast.ExpressionStatement)
+	if !ok {
+		t.Fatalf("program.Statements[0] is not %T. got=%T", &ast.ExpressionStatement{}, program.Statements[0])
+	}
+
+	literal, ok := stmt.Expression.(*ast.FloatLiteral)
+	if !ok {
+		t.Fatalf("exp not %T. got=%T", &ast.FloatLiteral{}, stmt.Expression)
+	}
+
+	if literal.Value != 3.25 {
+		t.Errorf("literal.Value not %g. got=%g", 3.25, literal.Value)
+	}
+
+	if literal.TokenLiteral() != "3.25" {
+		t.Errorf("literal.TokenLiteral not %s. got=%s", "3.25", literal.TokenLiteral())
+	}
+}
+
 func TestParsingPrefixExpressions(t *testing.T) {
 	prefixTests := []struct {
 		input    string
@@ -172,8 +245,18 @@ func TestParsingInfixExpressions(t *testing.T) {
 		{"5 - 5;", 5, "-", 5},
 		{"5 * 5;", 5, "*", 5},
 		{"5 / 5;", 5, "/", 5},
+		{"5 ~/ 5;", 5, "~/", 5},
 		{"5 > 5;", 5, ">", 5},
 		{"5 < 5;", 5, "<", 5},
+		{"5 >= 5;", 5, ">=", 5},
+		{"5 <= 5;", 5, "<=", 5},
+		{"5 % 5;", 5, "%", 5},
+		{"5 ** 5;", 5, "**", 5},
+		{"5 & 5;", 5, "&", 5},
+		{"5 | 5;", 5, "|", 5},
+		{"5 ^ 5;", 5, "^", 5},
+		{"5 << 5;", 5, "<<", 5},
+		{"5 >> 5;", 5, ">>", 5},
 		{"5 == 5;", 5, "==", 5},
 		{"5 != 5;", 5, "!=", 5},
 		{"true == true", true, "==", true},
@@ -308,6 +391,70 @@ func TestOperatorPrecedenceParsing(t *testing.T) {
 			"add(a * b[2], b[1], 2 * [1, 2][1])",
 			"add((a * (b[2])), (b[1]), (2 * ([1, 2][1])))",
 		},
+		{
+			"a || b && c",
+			"(a || (b && c))",
+		},
+		{
+			"a && b || c && d",
+			"((a && b) || (c && d))",
+		},
+		{
+			"x != null && x[0] > 1",
+			"((x != null) && ((x[0]) > 1))",
+		},
+		{
+			"!a || b == c",
+			"((!a) || (b == c))",
+		},
+		{
+			"a | b ^ c & d",
+			"(a | (b ^ (c & d)))",
+		},
+		{
+			"a & b == c",
+			"(a & (b == c))",
+		},
+		{
+			"a << 1 + b < c >> 2",
+			"((a << (1 + b)) < (c >> 2))",
+		},
+		{
+			"a + b % c * d",
+			"(a + ((b % c) * d))",
+		},
+		{
+			"2 ** 3 ** 2",
+			"(2 ** (3 ** 2))",
+		},
+		{
+			"-2 ** 2",
+			"(-(2 ** 2))",
+		},
+		{
+			"a * b ** -c",
+			"(a * (b ** (-c)))",
+		},
+		{
+			"~a & b",
+			"((~a) & b)",
+		},
+		{
+			"1..n + 1",
+			"(1..(n + 1))",
+		},
+		{
+			"0..<len(xs) step 2 * k",
+			"(0..<len(xs) step (2 * k))",
+		},
+		{
+			"x in 1..10 == true",
+			"((x in (1..10)) == true)",
+		},
+		{
+			"a in b && c < d",
+			"((a in b) && (c < d))",
+		},
 	}
 
 	for _, tt := range tests {
@@ -320,6 +467,44 @@ func TestOperatorPrecedenceParsing(t *testing.T) {
 	}
 }
 
+func TestAssignExpressionParsing(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"x = 5", "(x = 5)"},
+		{"x += y * 2", "(x += (y * 2))"},
+		{"a = b = c", "(a = (b = c))"},
+		{"x = a || b", "(x = (a || b))"},
+		{"x %= 3", "(x %= 3)"},
+		{"arr[0] = 1", "((arr[0]) = 1)"},
+		{`h["k"] += 2`, "((h[k]) += 2)"},
+		{"grid[i][j] = x + 1", "(((grid[i])[j]) = (x + 1))"},
+	}
+
+	for _, tt := range tests {
+		program := createParseProgram(tt.input, t)
+
+		stmt := program.Statements[0].(*ast.ExpressionStatement)
+		if _, ok := stmt.Expression.(*ast.AssignExpression); !ok {
+			t.Fatalf("exp not %T. got=%T", &ast.AssignExpression{}, stmt.Expression)
+		}
+
+		if program.String() != tt.expected {
+			t.Errorf("expected=%q, got=%q", tt.expected, program.String())
+		}
+	}
+
+	l := lexer.New("1 + 2 = 3")
+	p := parser.New(l)
+	p.ParseProgram()
+
+	errors := p.Errors()
+	if len(errors) != 1 || errors[0] != "cannot assign to (1 + 2)" {
+		t.Errorf("wrong errors. got=%q", errors)
+	}
+}
+
 func TestIfExpression(t *testing.T) {
 	input := `if (x < y) { x }`
 
@@ -584,6 +769,226 @@ func TestCallExpressionParsing(t *testing.T) {
 	testInfixExpression(t, exp.Arguments[2], 4, "+", 5)
 }
 
+func TestDefaultParameterParsing(t *testing.T) {
+	program := createParseProgram("fn(x, y = 10, z = x * 2) { x };", t)
+
+	stmt := program.Statements[0].(*ast.ExpressionStatement)
+	function := stmt.Expression.(*ast.FunctionLiteral)
+
+	if len(function.Defaults) != 3 {
+		t.Fatalf("function.Defaults wrong length. want 3, got=%d", len(function.Defaults))
+	}
+	if function.Defaults[0] != nil {
+		t.Errorf("function.Defaults[0] not nil. got=%s", function.Defaults[0])
+	}
+	testLiteralExpression(t, function.Defaults[1], 10)
+	testInfixExpression(t, function.Defaults[2], "x", "*", 2)
+
+	if function.String() != "fn(x, y = 10, z = (x * 2))x" {
+		t.Errorf("function.String() wrong. got=%q", function.String())
+	}
+}
+
+func TestFunctionLiteralWithName(t *testing.T) {
+	program := createParseProgram("let add = fn(a, b) { a + b }; let sub += fn(a, b) { a - b };", t)
+
+	add := program.Statements[0].(*ast.LetStatement).Value.(*ast.FunctionLiteral)
+	if add.Name != "add" {
+		t.Errorf("function literal name wrong. want 'add', got=%q", add.Name)
+	}
+
+	sub := program.Statements[1].(*ast.LetStatement).Value.(*ast.FunctionLiteral)
+	if sub.Name != "" {
+		t.Errorf("function literal name wrong. want '', got=%q", sub.Name)
+	}
+}
+
+func TestArrowFunctionParsing(t *testing.T) {
+	tests := []struct {
+		input          string
+		expectedParams []string
+		expected       string
+	}{
+		{"x => x * 2", []string{"x"}, "fn(x)(x * 2)"},
+		{"(x) => x * 2", []string{"x"}, "fn(x)(x * 2)"},
+		{"() => 42", []string{}, "fn()42"},
+		{"(a, b = 1) => a + b", []string{"a", "b"}, "fn(a, b = 1)(a + b)"},
+		{"(x, ...rest) => rest", []string{"x"}, "fn(x, ...rest)rest"},
+		{"(x) => { let y = x; y }", []string{"x"}, "fn(x)let y = x;y"},
+		{"a => b => a + b", []string{"a"}, "fn(a)fn(b)(a + b)"},
+	}
+
+	for _, tt := range tests {
+		program := createParseProgram(tt.input, t)
+
+		stmt := program.Statements[0].(*ast.ExpressionStatement)
+		function, ok := stmt.Expression.(*ast.FunctionLiteral)
+		if !ok {
+			t.Fatalf("%q: stmt.Expression is not %T. got=%T", tt.input, &ast.FunctionLiteral{}, stmt.Expression)
+		}
+
+		if len(function.Parameters) != len(tt.expectedParams) {
+			t.Fatalf("%q: length parameters wrong. want %d, got=%d", tt.input, len(tt.expectedParams), len(function.Parameters))
+		}
+		for i, identifier := range tt.expectedParams {
+			testLiteralExpression(t, function.Parameters[i], identifier)
+		}
+
+		if function.String() != tt.expected {
+			t.Errorf("%q: String() wrong. expected=%q, got=%q", tt.input, tt.expected, function.String())
+		}
+	}
+}
+
+func TestArrowFunctionLookahead(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"(1 + 2) * 3", "((1 + 2) * 3)"},
+		{"(a)", "a"},
+		{"f((x) => x, (y))", "f(fn(x)x, y)"},
+		{"let double = (x) => x * 2;", "let double = fn(x)(x * 2);"},
+		{"map(xs, x => x + 1)", "map(xs, fn(x)(x + 1))"},
+		{"match (v) { n if ok => n }", "match (v) { n if ok => n }"},
+		{"match (v) { n if (ok) => n }", "match (v) { n if ok => n }"},
+		{"match (v) { n if any(xs, x => x > n) => n }", "match (v) { n if any(xs, fn(x)(x > n)) => n }"},
+	}
+
+	for _, tt := range tests {
+		program := createParseProgram(tt.input, t)
+
+		if program.String() != tt.expected {
+			t.Errorf("%q: String() wrong. expected=%q, got=%q", tt.input, tt.expected, program.String())
+		}
+	}
+}
+
+func TestArrowFunctionErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"(1) => 1;", "expected next token to be IDENT, got INT instead"},
+		{"(x) => ;", "no prefix parse function for ; found"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := parser.New(l)
+		p.ParseProgram()
+
+		errors := p.Errors()
+		if len(errors) == 0 {
+			t.Errorf("%q: expected a parser error", tt.input)
+			continue
+		}
+		if errors[0] != tt.expected {
+			t.Errorf("%q: wrong error. expected=%q, got=%q", tt.input, tt.expected, errors[0])
+		}
+	}
+}
+
+func TestNamedArgumentParsing(t *testing.T) {
+	program := createParseProgram("area(2, height: 3, unit: \"cm\");", t)
+
+	stmt := program.Statements[0].(*ast.ExpressionStatement)
+	exp := stmt.Expression.(*ast.CallExpression)
+
+	if len(exp.Arguments) != 1 {
+		t.Fatalf("wrong length of arguments. got=%d", len(exp.Arguments))
+	}
+	testLiteralExpression(t, exp.Arguments[0], 2)
+
+	if len(exp.NamedArguments) != 2 {
+		t.Fatalf("wrong length of named arguments. got=%d", len(exp.NamedArguments))
+	}
+	testIdentifier(t, exp.NamedArguments[0].Name, "height")
+	testLiteralExpression(t, exp.NamedArguments[0].Value, 3)
+	testIdentifier(t, exp.NamedArguments[1].Name, "unit")
+
+	if exp.String() != "area(2, height: 3, unit: cm)" {
+		t.Errorf("exp.String() wrong. got=%q", exp.String())
+	}
+}
+
+func TestDefaultAndNamedArgumentErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"f(x: 1, 2);", "positional argument after named argument"},
+		{"f(x: 1, 2) + 1;", "positional argument after named argument"},
+		{"f(1, x: 2;", "expected next token to be ), got ; instead"},
+		{"fn(x, 1) { x };", "expected next token to be IDENT, got INT instead"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := parser.New(l)
+		p.ParseProgram()
+
+		errors := p.Errors()
+		if len(errors) == 0 {
+			t.Errorf("%q: expected a parser error", tt.input)
+			continue
+		}
+		if errors[0] != tt.expected {
+			t.Errorf("%q: wrong error. expected=%q, got=%q", tt.input, tt.expected, errors[0])
+		}
+	}
+}
+
+func TestRestAndSpreadParsing(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"fn(first, ...rest) { rest }", "fn(first, ...rest)rest"},
+		{"fn(...args) { args }", "fn(...args)args"},
+		{"fn(x = 1, ...xs) { x }", "fn(x = 1, ...xs)x"},
+		{"f(...args)", "f(...args)"},
+		{"f(1, ...xs, 2, n: 3)", "f(1, ...xs, 2, n: 3)"},
+		{"[...a, 1, ...b + c]", "[...a, 1, ...(b + c)]"},
+		{"[...1..3]", "[...(1..3)]"},
+		{`{...defaults, "a": 1, ...overrides}`, "{...defaults, a:1, ...overrides}"},
+	}
+
+	for _, tt := range tests {
+		program := createParseProgram(tt.input, t)
+
+		stmt := program.Statements[0].(*ast.ExpressionStatement)
+		if stmt.Expression.String() != tt.expected {
+			t.Errorf("%q: String() wrong. expected=%q, got=%q", tt.input, tt.expected, stmt.Expression.String())
+		}
+	}
+}
+
+func TestRestParameterErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"fn(...rest, x) { x };", "the rest parameter must be the last parameter"},
+		{"fn(...) { 1 };", "expected next token to be IDENT, got ) instead"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := parser.New(l)
+		p.ParseProgram()
+
+		errors := p.Errors()
+		if len(errors) == 0 {
+			t.Errorf("%q: expected a parser error", tt.input)
+			continue
+		}
+		if errors[0] != tt.expected {
+			t.Errorf("%q: wrong error. expected=%q, got=%q", tt.input, tt.expected, errors[0])
+		}
+	}
+}
+
 func TestStringLiteralExpression(t *testing.T) {
 	input := `"hello world";`
 
@@ -603,6 +1008,105 @@ func TestStringLiteralExpression(t *testing.T) {
 	}
 }
 
+func TestRawAndTripleQuotedStrings(t *testing.T) {
+	tests := []struct {
+		input         string
+		expected      string
+		expectedStart string
+		expectedEnd   string
+	}{
+		{"`C:\\dir\\n \"x\"`", `C:\dir\n "x"`, "1:1", "1:15"},
+		{"`line one\n  line two`", "line one\n  line two", "1:1", "2:12"},
+		{"\"\"\"\n    SELECT *\n      FROM t\n    \"\"\"", "SELECT *\n  FROM t", "1:1", "4:8"},
+		{`"""say "hi" \n"""`, `say "hi" \n`, "1:1", "1:18"},
+	}
+
+	for _, tt := range tests {
+		program := createParseProgram(tt.input, t)
+		stmt := program.Statements[0].(*ast.ExpressionStatement)
+
+		literal, ok := stmt.Expression.(*ast.StringLiteral)
+		if !ok {
+			t.Fatalf("exp not %T. got=%T", &ast.StringLiteral{}, stmt.Expression)
+		}
+
+		if literal.Value != tt.expected {
+			t.Errorf("literal.Value wrong. expected=%q, got=%q", tt.expected, literal.Value)
+		}
+
+		if literal.Pos().String() != tt.expectedStart {
+			t.Errorf("%q: start wrong. expected=%s, got=%s", tt.input, tt.expectedStart, literal.Pos())
+		}
+
+		if literal.End().String() != tt.expectedEnd {
+			t.Errorf("%q: end wrong. expected=%s, got=%s", tt.input, tt.expectedEnd, literal.End())
+		}
+	}
+}
+
+func TestInterpolatedStringParsing(t *testing.T) {
+	input := `"Hello ${name}, you have ${len(items)} items"`
+
+	program := createParseProgram(input, t)
+	stmt := program.Statements[0].(*ast.ExpressionStatement)
+
+	str, ok := stmt.Expression.(*ast.InterpolatedString)
+	if !ok {
+		t.Fatalf("exp not %T. got=%T", &ast.InterpolatedString{}, stmt.Expression)
+	}
+
+	if len(str.Parts) != 5 {
+		t.Fatalf("str.Parts has wrong length. expected=5, got=%d", len(str.Parts))
+	}
+
+	literals := map[int]string{0: "Hello ", 2: ", you have ", 4: " items"}
+	for i, expected := range literals {
+		literal, ok := str.Parts[i].(*ast.StringLiteral)
+		if !ok {
+			t.Fatalf("str.Parts[%d] not %T. got=%T", i, &ast.StringLiteral{}, str.Parts[i])
+		}
+		if literal.Value != expected {
+			t.Errorf("str.Parts[%d] wrong value. expected=%q, got=%q", i, expected, literal.Value)
+		}
+	}
+
+	testIdentifier(t, str.Parts[1], "name")
+
+	if _, ok := str.Parts[3].(*ast.CallExpression); !ok {
+		t.Errorf("str.Parts[3] not %T. got=%T", &ast.CallExpression{}, str.Parts[3])
+	}
+
+	if str.String() != input {
+		t.Errorf("str.String() wrong. expected=%q, got=%q", input, str.String())
+	}
+}
+
+func TestInterpolatedStringErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`"a ${} b"`, "empty expression in string interpolation"},
+		{`"a ${x y} b"`, "expected } to close string interpolation, got IDENT instead"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := parser.New(l)
+		p.ParseProgram()
+
+		errors := p.Errors()
+		if len(errors) != 1 {
+			t.Errorf("%s: expected 1 error, got=%d %q", tt.input, len(errors), errors)
+			continue
+		}
+
+		if errors[0] != tt.expected {
+			t.Errorf("%s: wrong error. expected=%q, got=%q", tt.input, tt.expected, errors[0])
+		}
+	}
+}
+
 func TestParsingHashLiteralsStringKeys(t *testing.T) {
 	input := `{"one": 1, "two": 2, "three": 3}`
 
@@ -965,6 +1469,180 @@ func TestForExpression(t *testing.T) {
 
 }
 
+func TestForInExpressionParsing(t *testing.T) {
+	tests := []struct {
+		input         string
+		expectedKey   string
+		expectedValue string
+		expected      string
+	}{
+		{"for (x in xs) { x }", "", "x", "for (x in xs) x"},
+		{"for (k, v in hash) { v }", "k", "v", "for (k, v in hash) v"},
+		{"for (i, x in [1, 2]) { i }", "i", "x", "for (i, x in [1, 2]) i"},
+	}
+
+	for _, tt := range tests {
+		program := createParseProgram(tt.input, t)
+
+		stmt := program.Statements[0].(*ast.ExpressionStatement)
+		forIn, ok := stmt.Expression.(*ast.ForInExpression)
+		if !ok {
+			t.Fatalf("exp not %T. got=%T", &ast.ForInExpression{}, stmt.Expression)
+		}
+
+		if tt.expectedKey == "" && forIn.Key != nil {
+			t.Errorf("forIn.Key not nil. got=%s", forIn.Key)
+		}
+		if tt.expectedKey != "" {
+			testIdentifier(t, forIn.Key, tt.expectedKey)
+		}
+		testIdentifier(t, forIn.Value, tt.expectedValue)
+
+		if forIn.String() != tt.expected {
+			t.Errorf("forIn.String() wrong. expected=%q, got=%q", tt.expected, forIn.String())
+		}
+	}
+}
+
+func TestMatchExpressionParsing(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`match (x) { 1 => "one", _ => "many" }`, `match (x) { 1 => one, _ => many }`},
+		{`match (x) { -1 | 0 => a, n if n > 0 => n }`, `match (x) { (-1) | 0 => a, n if (n > 0) => n }`},
+		{`match (x) { [first, ..rest] => first, [] => 0 }`, `match (x) { [first, ..rest] => first, [] => 0 }`},
+		{`match (x) { [a, ..] => a }`, `match (x) { [a, ..] => a }`},
+		{`match (s) { {"type": "circle", "r": r} => r, {name} => name }`, `match (s) { {"type": "circle", "r": r} => r, {"name": name} => name }`},
+		{`match (x) { true => { let y = 1; y } false => 0 }`, `match (x) { true => let y = 1;y, false => 0 }`},
+		{`match (x) { null => 0, 1.5 => 1, }`, `match (x) { null => 0, 1.5 => 1 }`},
+	}
+
+	for _, tt := range tests {
+		program := createParseProgram(tt.input, t)
+
+		stmt := program.Statements[0].(*ast.ExpressionStatement)
+		match, ok := stmt.Expression.(*ast.MatchExpression)
+		if !ok {
+			t.Fatalf("exp not %T. got=%T", &ast.MatchExpression{}, stmt.Expression)
+		}
+
+		if match.String() != tt.expected {
+			t.Errorf("match.String() wrong. expected=%q, got=%q", tt.expected, match.String())
+		}
+	}
+}
+
+func TestDestructuringLetStatements(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"let [a, b, ..rest] = arr;", "let [a, b, ..rest] = arr;"},
+		{"let [_, second] = pair", "let [_, second] = pair;"},
+		{"let {name, age: years} = person;", `let {"name": name, "age": years} = person;`},
+		{"let [x, [y, z], {k}] = nested;", `let [x, [y, z], {"k": k}] = nested;`},
+		{`let [a, b = 10] = xs;`, "let [a, b = 10] = xs;"},
+		{`let {name = "anon", "age": age = a + 1} = p;`, `let {"name": name = anon, "age": age = (a + 1)} = p;`},
+	}
+
+	for _, tt := range tests {
+		program := createParseProgram(tt.input, t)
+
+		stmt, ok := program.Statements[0].(*ast.LetStatement)
+		if !ok {
+			t.Fatalf("stmt not %T. got=%T", &ast.LetStatement{}, program.Statements[0])
+		}
+		if stmt.Pattern == nil {
+			t.Fatalf("stmt.Pattern is nil")
+		}
+
+		if stmt.String() != tt.expected {
+			t.Errorf("stmt.String() wrong. expected=%q, got=%q", tt.expected, stmt.String())
+		}
+	}
+}
+
+func TestMatchPatternErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"match (x) { [..rest, a] => a }", "the rest element must be the last element of an array pattern"},
+		{"match (x) { - a => a }", "expected a number after - in pattern, got IDENT instead"},
+		{"match (x) { (1) => 1 }", "expected a pattern, got ( instead"},
+		{"match (x) { 1 => 1 2 => 2 }", "expected next token to be ,, got INT instead"},
+		{`match (x) { {"a"} => 1 }`, "expected next token to be :, got } instead"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := parser.New(l)
+		p.ParseProgram()
+
+		errors := p.Errors()
+		if len(errors) == 0 {
+			t.Errorf("%q: expected a parser error", tt.input)
+			continue
+		}
+		if errors[0] != tt.expected {
+			t.Errorf("%q: wrong error. expected=%q, got=%q", tt.input, tt.expected, errors[0])
+		}
+	}
+}
+
+func TestDestructuringLetErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"let [a, 1] = xs;", "expected a name, array pattern or hash pattern, got INT instead"},
+		{"let [a, b] += xs;", "expected next token to be =, got += instead"},
+		{"let {a | b} = h;", "expected next token to be ,, got | instead"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := parser.New(l)
+		p.ParseProgram()
+
+		errors := p.Errors()
+		if len(errors) == 0 {
+			t.Errorf("%q: expected a parser error", tt.input)
+			continue
+		}
+		if errors[0] != tt.expected {
+			t.Errorf("%q: wrong error. expected=%q, got=%q", tt.input, tt.expected, errors[0])
+		}
+	}
+}
+
+func TestForExpressionWithoutLet(t *testing.T) {
+	program := createParseProgram("for (i = 0; i < 10; i += 1) { i }", t)
+
+	stmt := program.Statements[0].(*ast.ExpressionStatement)
+	forExp, ok := stmt.Expression.(*ast.ForExpression)
+	if !ok {
+		t.Fatalf("exp not %T. got=%T", &ast.ForExpression{}, stmt.Expression)
+	}
+
+	initialization, ok := forExp.Initialization.(*ast.ExpressionStatement)
+	if !ok {
+		t.Fatalf("forExp.Initialization not %T. got=%T", &ast.ExpressionStatement{}, forExp.Initialization)
+	}
+	if initialization.String() != "(i = 0)" {
+		t.Errorf("wrong initialization. got=%q", initialization.String())
+	}
+
+	step, ok := forExp.IncrementOrDecrement.(*ast.ExpressionStatement)
+	if !ok {
+		t.Fatalf("forExp.IncrementOrDecrement not %T. got=%T", &ast.ExpressionStatement{}, forExp.IncrementOrDecrement)
+	}
+	if step.String() != "(i += 1)" {
+		t.Errorf("wrong step. got=%q", step.String())
+	}
+}
+
 func TestWhileExpression(t *testing.T) {
 	input := `while (i < 10) { i }`
 
@@ -1006,3 +1684,83 @@ func TestWhileExpression(t *testing.T) {
 	}
 
 }
+
+func TestNodePositions(t *testing.T) {
+	input := "let x = 1;\nlet y = add(x,\n  2 * 3);"
+
+	program := createParseProgram(input, t)
+
+	tests := []struct {
+		node          ast.Node
+		expectedStart string
+		expectedEnd   string
+	}{
+		{program, "1:1", "3:10"},
+		{program.Statements[0], "1:1", "1:11"},
+		{program.Statements[1], "2:1", "3:10"},
+		{program.Statements[1].(*ast.LetStatement).Name, "2:5", "2:6"},
+		{program.Statements[1].(*ast.LetStatement).Value, "2:9", "3:9"},
+		{program.Statements[1].(*ast.LetStatement).Value.(*ast.CallExpression).Arguments[1], "3:3", "3:8"},
+	}
+
+	for i, tt := range tests {
+		if tt.node.Pos().String() != tt.expectedStart {
+			t.Errorf("tests[%d] - start wrong. expected=%s, got=%s", i, tt.expectedStart, tt.node.Pos())
+		}
+		if tt.node.End().String() != tt.expectedEnd {
+			t.Errorf("tests[%d] - end wrong. expected=%s, got=%s", i, tt.expectedEnd, tt.node.End())
+		}
+	}
+}
+
+func TestParserErrorRecovery(t *testing.T) {
+	input := `
+		let a = 1;
+		let b = (2 + ;
+		let c = 3;
+		let = 4;
+		let add = fn(x, y) {
+			let z = x + ;
+			x + y;
+		};
+		if (a > { b }
+		let d = add(a, c);
+	`
+
+	l := lexer.New(input)
+	p := parser.New(l)
+	program := p.ParseProgram()
+
+	expectedErrors := []string{
+		"no prefix parse function for ; found",
+		"expected next token to be IDENT, got = instead",
+		"no prefix parse function for ; found",
+		"expected next token to be :, got } instead",
+	}
+
+	errors := p.Errors()
+	if len(errors) != len(expectedErrors) {
+		t.Fatalf("wrong number of errors. expected=%d, got=%d (%q)", len(expectedErrors), len(errors), errors)
+	}
+	for i, msg := range expectedErrors {
+		if errors[i] != msg {
+			t.Errorf("errors[%d] wrong. expected=%q, got=%q", i, msg, errors[i])
+		}
+	}
+
+	expectedNames := []string{"a", "c", "add", "d"}
+	var names []string
+	for _, stmt := range program.Statements {
+		if let, ok := stmt.(*ast.LetStatement); ok {
+			names = append(names, let.Name.Value)
+		}
+	}
+	if fmt.Sprint(names) != fmt.Sprint(expectedNames) {
+		t.Errorf("wrong statements recovered. expected=%v, got=%v", expectedNames, names)
+	}
+
+	add := program.Statements[2].(*ast.LetStatement).Value.(*ast.FunctionLiteral)
+	if len(add.Body.Statements) != 1 {
+		t.Errorf("add.Body.Statements wrong. expected 1 statement, got=%d", len(add.Body.Statements))
+	}
+}
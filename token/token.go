@@ -1,10 +1,54 @@
 package token
 
+import "fmt"
+
 type TokenType string // Type of token (e.g. IDENT, INT, ASSIGN, PLUS, etc.)
 
 type Token struct {
 	Type    TokenType // Type of token (e.g. IDENT, INT, ASSIGN, PLUS, etc.)
 	Literal string    // Literal value of token (e.g. "foobar", "123", "+", etc.)
+	Pos     Position  // Position of the first character of the token
+	End     Position  // Position immediately after the last character of the token
+	Leading []Trivia  // Comments between the previous token and this one
+}
+
+// Trivia is source text with no meaning to the parser, such as a comment. It
+// is kept on the following token so tools like formatters can recover it.
+type Trivia struct {
+	Type    TokenType // LINECOMMENT or BLOCKCOMMENT
+	Literal string    // Text of the comment including its delimiters
+	Pos     Position
+	End     Position
+}
+
+// Position is a location in the source. Line and Column are 1-based, Offset
+// is the 0-based byte offset into the input.
+type Position struct {
+	Offset int
+	Line   int
+	Column int
+}
+
+// IsValid reports whether the position has been set
+func (p Position) IsValid() bool {
+	return p.Line > 0
+}
+
+func (p Position) String() string {
+	if !p.IsValid() {
+		return "-"
+	}
+	return fmt.Sprintf("%d:%d", p.Line, p.Column)
+}
+
+// Span is the half-open source range [Start, End)
+type Span struct {
+	Start Position
+	End   Position
+}
+
+func (s Span) String() string {
+	return s.Start.String() + "-" + s.End.String()
 }
 
 // Define constants for the different token types
@@ -12,11 +56,22 @@ const (
 	ILLEGAL = "ILLEGAL" // Token/character we don't know about
 	EOF     = "EOF"     // End of file
 
+	// Comments, only ever found in Token.Leading
+	LINECOMMENT  = "LINECOMMENT"  // // comment
+	BLOCKCOMMENT = "BLOCKCOMMENT" // /* comment */
+
 	// Identifiers + literals
 	IDENTFIER = "IDENT"  // add, foobar, x, y, ...
 	INT       = "INT"    // 1234567890
+	FLOAT     = "FLOAT"  // 3.14
 	STRING    = "STRING" // "foobar"
 
+	// Parts of an interpolated string such as "a ${x} b ${y} c", with the
+	// embedded expressions lexed as ordinary tokens in between
+	STRINGHEAD = "STRINGHEAD" // "a ${
+	STRINGMID  = "STRINGMID"  // } b ${
+	STRINGTAIL = "STRINGTAIL" // } c"
+
 	// Operators
 	ASSIGN     = "="
 	PLUS       = "+"
@@ -24,10 +79,12 @@ const (
 	BANG       = "!"
 	ASTERISK   = "*"
 	SLASH      = "/"
+	INTDIVIDE  = "~/"
 	BITWISEAND = "&"
 	BITWISEOR  = "|"
 	MODULO     = "%"
 	BITWISEXOR = "^"
+	BITWISENOT = "~"
 
 	// Comparison operators
 	EQUAL              = "=="
@@ -57,10 +114,17 @@ const (
 	BITWISEXOREQUAL = "^="
 	POW             = "**"
 
+	// Range operators
+	DOTDOT     = ".."  // 1..10
+	DOTDOTLESS = "..<" // 0..<10
+
+	ELLIPSIS = "..." // fn(...rest), f(...args)
+
 	// Delimiters
 	COMMA     = ","
 	SEMICOLON = ";"
 	COLON     = ":"
+	ARROW     = "=>"
 
 	LPAREN   = "("
 	RPAREN   = ")"
@@ -82,6 +146,8 @@ const (
 	CONTINUE = "CONTINUE" // continue;
 	WHILE    = "WHILE"    // while (x < y) { return true; }
 	FOR      = "FOR"      // for (i = 0; i < 10; i++) { return true; }
+	IN       = "IN"       // for (x in xs) { puts(x); }
+	MATCH    = "MATCH"    // match (x) { 1 => "one", _ => "many" }
 	NULL     = "NULL"     // null
 )
 
@@ -98,6 +164,8 @@ var keywords = map[string]TokenType{
 	"continue": CONTINUE,
 	"while":    WHILE,
 	"for":      FOR,
+	"in":       IN,
+	"match":    MATCH,
 	"null":     NULL,
 }
 